@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"github.com/spf13/cobra"
+	"github.com/sirupsen/logrus"
 	"axia/internal/axiom"
 	"axia/internal/logging"
 	"axia/internal/trust"
@@ -15,46 +17,177 @@ import (
 	"time"
 	"github.com/google/uuid"
 	"axia/internal/storage/ipfs"
-	"axia/internal/auth"
+	authpkg "axia/internal/auth"
+	"axia/internal/crypto"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"axia/internal/cli"
+	"axia/internal/keystore"
+	"axia/internal/status"
+	"axia/internal/translog"
+	"axia/internal/timestamp"
+	"axia/internal/subjective"
+	"axia/internal/graph"
+	"axia/internal/did"
+	"crypto/ed25519"
+	"strings"
 )
 
 func main() {
 	logger := logging.NewLogger()
 
-	// Check for secret key
-	if os.Getenv("AXIA_SECRET_KEY") == "" {
-		logger.Fatal("AXIA_SECRET_KEY environment variable must be set")
-	}
-
-	auth, err := auth.NewAuthenticator(logger)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize authenticator")
-	}
+	var (
+		auth    *authpkg.Authenticator
+		db      *database.DB
+		network *trust.Network
+		manager *axiom.Manager
+		tlog    *translog.Log
+		tsa     *timestamp.Authority
+		nodeKey *crypto.KeyPair
+	)
 
 	// Add authentication context to all operations
 	ctx := context.WithValue(context.Background(), "secret_key", os.Getenv("AXIA_SECRET_KEY"))
 
-	// Initialize database with auth context
-	db, err := database.New(database.Config{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     5432,
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Database: os.Getenv("DB_NAME"),
-	}, logger)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to connect to database")
-	}
-	defer db.Close()
-
-	// Pass authenticated context to components
-	network := trust.NewNetwork(logger, db, auth)
-	manager := axiom.NewManager(logger, db, auth)
-
 	var rootCmd = &cobra.Command{
 		Use:   "axios",
 		Short: "Axiomatic Trust Graph CLI",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Proofs are hashed with the configured algorithm, offline too
+			if err := crypto.SetDefaultHash(os.Getenv("AXIA_HASH_ALGORITHM")); err != nil {
+				return fmt.Errorf("invalid AXIA_HASH_ALGORITHM: %w", err)
+			}
+
+			// Offline commands only touch local files
+			if cli.IsOffline(cmd) {
+				return nil
+			}
+
+			// Check for secret key
+			if os.Getenv("AXIA_SECRET_KEY") == "" {
+				return fmt.Errorf("AXIA_SECRET_KEY environment variable must be set")
+			}
+
+			var err error
+			auth, err = authpkg.NewAuthenticator(logger)
+			if err != nil {
+				return fmt.Errorf("failed to initialize authenticator: %w", err)
+			}
+
+			// Initialize database with auth context
+			db, err = database.New(database.Config{
+				Host:     os.Getenv("DB_HOST"),
+				Port:     5432,
+				User:     os.Getenv("DB_USER"),
+				Password: os.Getenv("DB_PASSWORD"),
+				Database: os.Getenv("DB_NAME"),
+			}, logger)
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+
+			// Pass authenticated context to components
+			network = trust.NewNetwork(logger, db, auth)
+			manager = axiom.NewManager(logger, db, auth)
+
+			// Old claims lose weight by the configured half-lives
+			halfLives, err := trust.ParseHalfLives(os.Getenv("AXIA_HALF_LIFE"), os.Getenv("AXIA_TAG_HALF_LIVES"))
+			if err != nil {
+				return fmt.Errorf("invalid claim half-lives: %w", err)
+			}
+			network.SetHalfLives(halfLives)
+
+			// Claims are signed with the node key unless the agent has its own
+			nodeKey, err = loadNodeKey()
+			if err != nil {
+				return fmt.Errorf("failed to load node signing key: %w", err)
+			}
+			manager.SetDefaultSigner(nodeKey)
+			manager.SetChainStore(db)
+			// DID documents are resolved once while the stored claims are
+			// restored
+			resolver := cli.NewResolver()
+			manager.SetResolver(did.NewCache(resolver))
+
+			// The node key is the custodian of agents without keys of their
+			// own, such as twitter: reporters; agents in the keystore are
+			// bound to their keys
+			if err := registerCustodians(cmd, manager, logger); err != nil {
+				return err
+			}
+			network.SetResolver(manager.Resolver())
+			db.SetResolver(manager.Resolver())
+			network.SetRevocationVerifier(manager)
+
+			// Accepted claims are appended to the transparency log, whose
+			// tree heads are signed with the node key
+			tlog, err = translog.Open(context.Background(), db.LogStorage(), nodeKey, logger)
+			if err != nil {
+				return fmt.Errorf("failed to open transparency log: %w", err)
+			}
+			db.SetLog(tlog)
+
+			// The node is the timestamp authority of the claims it accepts
+			tsa, err = timestamp.NewAuthority(context.Background(), db.TimestampStorage(), nodeKey, logger)
+			if err != nil {
+				return fmt.Errorf("failed to open timestamp authority: %w", err)
+			}
+
+			// Restore the key history before any claim is verified
+			rotations, err := db.Rotations(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, rotation := range rotations {
+				if err := manager.AddRotation(rotation); err != nil {
+					return fmt.Errorf("invalid stored key rotation for %s: %w", rotation.Issuer, err)
+				}
+			}
+
+			// Restore the trust network, then the revocation status list
+			// whose revocations are checked against the claims
+			claims, err := db.QueryClaims(context.Background(), map[string]interface{}{"include_revoked": true})
+			if err != nil {
+				return err
+			}
+			for _, claim := range claims {
+				if err := network.AddClaim(claim); err != nil {
+					logger.WithError(err).WithField("claim_id", axiom.ClaimID(claim)).Warn("Skipping stored claim that no longer verifies")
+				}
+			}
+			revocations, err := db.Revocations(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, entry := range revocations {
+				if err := network.AddRevocation(entry.Revocation, entry.StatusIndex); err != nil {
+					return fmt.Errorf("invalid stored revocation of %s: %w", entry.Revocation.ClaimID, err)
+				}
+			}
+
+			// Restored claims and revocations were logged, and claims
+			// timestamped, when first accepted; only new ones are
+			// appended and stamped. Tokens from the authorities of trusted
+			// peers are kept.
+			network.SetLog(tlog)
+			var trustedAuthorities []string
+			for _, authority := range strings.Split(os.Getenv("AXIA_TIMESTAMP_AUTHORITIES"), ",") {
+				if authority = strings.TrimSpace(authority); authority != "" {
+					trustedAuthorities = append(trustedAuthorities, authority)
+				}
+			}
+			network.SetTimestamper(tsa, trustedAuthorities...)
+			manager.SetResolver(resolver)
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if db != nil {
+				db.Close()
+			}
+		},
 	}
+	cli.AddKeystoreFlags(rootCmd)
 
 	var claimCmd = &cobra.Command{
 		Use:   "claim",
@@ -68,13 +201,92 @@ func main() {
 			axiomText, _ := cmd.Flags().GetString("axiom")
 			confidence, _ := cmd.Flags().GetFloat64("confidence")
 			tags, _ := cmd.Flags().GetStringSlice("tags")
+			canonicalization, _ := cmd.Flags().GetString("canonicalization")
+			format, _ := cmd.Flags().GetString("format")
+			lifetime, _ := cmd.Flags().GetDuration("jwt-lifetime")
+			capabilityPath, _ := cmd.Flags().GetString("capability")
+			cosigners, _ := cmd.Flags().GetStringSlice("cosigners")
+			threshold, _ := cmd.Flags().GetInt("threshold")
+			recipients, _ := cmd.Flags().GetStringSlice("recipients")
+			concealed, _ := cmd.Flags().GetStringSlice("conceal")
+
+			if format != "json" && format != "jwt" {
+				return fmt.Errorf("unsupported format %q: use json or jwt", format)
+			}
+			if err := manager.SetCanonicalization(canonicalization); err != nil {
+				return err
+			}
+
+			// Sign with the agent's own key when the keystore holds one
+			if err := registerAgentKey(cmd, manager, agent, logger); err != nil {
+				return err
+			}
+
+			// Delegated keys attach the chain that authorizes them
+			if capabilityPath != "" {
+				chain, err := cli.ReadCapabilities(capabilityPath)
+				if err != nil {
+					return err
+				}
+				manager.RegisterCapabilities(agent, chain)
+			}
 
-			claim, err := manager.CreateClaim(agent, subject, axiomText, confidence, tags)
+			var claim *axiom.Claim
+			var err error
+			if len(cosigners) > 0 && len(recipients) > 0 {
+				return fmt.Errorf("private claims cannot be co-signed")
+			}
+			if len(concealed) > 0 && (len(cosigners) > 0 || len(recipients) > 0) {
+				return fmt.Errorf("--conceal cannot be combined with --cosigners or --recipients")
+			}
+			if len(concealed) > 0 {
+				claim, err = manager.CreateSelectiveClaim(agent, subject, axiomText, confidence, tags, concealed)
+			} else if len(recipients) > 0 {
+				claim, err = manager.CreatePrivateClaim(agent, subject, axiomText, confidence, tags, recipients)
+			} else if len(cosigners) > 0 {
+				// The issuer counts towards the threshold
+				if threshold == 0 {
+					threshold = len(cosigners) + 1
+				}
+				claim, err = manager.CreateCoSignedClaim(agent, subject, axiomText, confidence, tags, &axiom.ThresholdPolicy{
+					Threshold: threshold,
+					Signers:   append([]string{agent}, cosigners...),
+				})
+			} else {
+				claim, err = manager.CreateClaim(agent, subject, axiomText, confidence, tags)
+			}
 			if err != nil {
 				return err
 			}
 
-			return network.AddClaim(claim)
+			// Claims awaiting co-signatures are printed for "axios cosign"
+			// and only added once complete
+			if axiom.ThresholdMet(claim) {
+				if err := network.AddClaim(claim); err != nil {
+					return err
+				}
+				if err := db.StoreClaim(context.Background(), claim); err != nil {
+					return err
+				}
+			} else {
+				logger.WithField("claim_id", claim.ID).Info("Claim awaits co-signatures")
+			}
+
+			if format == "jwt" {
+				token, err := manager.EncodeJWT(claim, lifetime)
+				if err != nil {
+					return fmt.Errorf("failed to encode claim as JWT: %w", err)
+				}
+				fmt.Println(token)
+				return nil
+			}
+
+			data, err := json.MarshalIndent(claim, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
 		},
 	}
 
@@ -82,16 +294,49 @@ func main() {
 		Use:   "truth",
 		Short: "Query the trust network",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts := trust.QueryOptions{
-				Observer:      cmd.Flag("observer").Value.String(),
-				Agent:        cmd.Flag("agent").Value.String(),
-				Subject:      cmd.Flag("subject").Value.String(),
-				Tags:         cmd.Flag("tags").Value.String(),
-				Depth:        cmd.Flag("depth").Value.String(),
-				MinConfidence: cmd.Flag("min-confidence").Value.String(),
-				MaxConfidence: cmd.Flag("max-confidence").Value.String(),
-				UseConsensus: cmd.Flag("consensus").Value.String() == "true",
-				UseTrustDecay: cmd.Flag("decay").Value.String() == "true",
+			opts := trust.QueryOptions{}
+			opts.Observer, _ = cmd.Flags().GetString("observer")
+			opts.Agent, _ = cmd.Flags().GetString("agent")
+			opts.Subject, _ = cmd.Flags().GetString("subject")
+			opts.Axiom, _ = cmd.Flags().GetString("axiom")
+			opts.Tags, _ = cmd.Flags().GetStringSlice("tags")
+			opts.Depth, _ = cmd.Flags().GetInt("depth")
+			opts.MinConfidence, _ = cmd.Flags().GetFloat64("min-confidence")
+			opts.MaxConfidence, _ = cmd.Flags().GetFloat64("max-confidence")
+			opts.UseConsensus, _ = cmd.Flags().GetBool("consensus")
+			opts.UseTrustDecay, _ = cmd.Flags().GetBool("decay")
+			opts.IncludeRevoked, _ = cmd.Flags().GetBool("include-revoked")
+			opts.Rank, _ = cmd.Flags().GetString("rank")
+			opts.Fusion, _ = cmd.Flags().GetString("fusion")
+			opts.Seeds, _ = cmd.Flags().GetStringSlice("seeds")
+			opts.Capacities, _ = cmd.Flags().GetIntSlice("capacities")
+
+			decayModel, _ := cmd.Flags().GetString("decay-model")
+			decayRate, _ := cmd.Flags().GetFloat64("decay-rate")
+			tnorm, _ := cmd.Flags().GetString("t-norm")
+			var err error
+			if opts.Decay, err = trust.NewDecayModel(decayModel, decayRate, opts.Depth); err != nil {
+				return err
+			}
+			if opts.TNorm, err = trust.LookupTNorm(tnorm); err != nil {
+				return err
+			}
+
+			// Private claims addressed to the observer are decrypted with
+			// the observer's keystore key, which is only unlocked when the
+			// query could find such claims
+			if opts.Observer != "" {
+				recipient, err := cli.AgentKeyID(cmd, logger, opts.Observer)
+				if err != nil && !errors.Is(err, keystore.ErrKeyNotFound) {
+					return err
+				}
+				if err == nil && network.HasPrivateClaims(opts, recipient) {
+					key, err := cli.UnlockAgentKey(cmd, logger, opts.Observer)
+					if err != nil {
+						return err
+					}
+					opts.Keys = append(opts.Keys, key)
+				}
 			}
 
 			results, err := network.Query(opts)
@@ -99,7 +344,220 @@ func main() {
 				return err
 			}
 
-			// Format and display results
+			var output interface{} = results
+			conflicts, _ := cmd.Flags().GetBool("conflicts")
+			if opts.UseConsensus || conflicts {
+				extended := map[string]interface{}{"results": results}
+				if opts.UseConsensus {
+					report, err := network.Consensus(opts)
+					if err != nil {
+						return err
+					}
+					extended["consensus"] = report
+				}
+				if conflicts {
+					disputes, err := network.Conflicts(opts)
+					if err != nil {
+						return err
+					}
+					extended["conflicts"] = disputes
+				}
+				output = extended
+			}
+
+			data, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	var reputationCmd = &cobra.Command{
+		Use:   "reputation",
+		Short: "List agents by global reputation",
+		Long: `Rank the agents and subjects of the trust network by EigenTrust global
+reputation. Trust restarts at the --pretrusted agents, which bounds what a
+group of colluding agents can gain by vouching for each other; without
+them every agent is pretrusted equally.
+
+With --observer, agents are ranked by personalized PageRank from the
+observer instead, leaving out agents it cannot reach.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pretrusted, _ := cmd.Flags().GetStringSlice("pretrusted")
+			observer, _ := cmd.Flags().GetString("observer")
+			top, _ := cmd.Flags().GetInt("top")
+			if observer != "" && len(pretrusted) > 0 {
+				return fmt.Errorf("--observer cannot be combined with --pretrusted")
+			}
+
+			var reputations []*trust.Reputation
+			if observer != "" {
+				reputations = network.PersonalizedReputation(observer)
+			} else {
+				reputations = network.GlobalReputation(pretrusted...)
+			}
+			if top > 0 && len(reputations) > top {
+				reputations = reputations[:top]
+			}
+			for i, reputation := range reputations {
+				fmt.Printf("%4d  %.6f  %s\n", i+1, reputation.Score, reputation.Agent)
+			}
+			return nil
+		},
+	}
+
+	var revokeCmd = &cobra.Command{
+		Use:   "revoke [claim-id]",
+		Short: "Revoke a stored claim",
+		Long: `Revoke a stored claim. The revocation is signed with the issuer's
+keystore key, or the node key for agents without one, and the claim is set
+in the published revocation status list at its transparency log index.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := auth.ValidateContext(ctx); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			reason, _ := cmd.Flags().GetString("reason")
+
+			claim, err := db.GetClaim(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := registerAgentKey(cmd, manager, claim.Issuer, logger); err != nil {
+				return err
+			}
+			revocation, err := manager.RevokeClaim(claim, reason)
+			if err != nil {
+				return err
+			}
+			if err := manager.VerifyRevocation(context.Background(), revocation, claim); err != nil {
+				return fmt.Errorf("revocation is not authorized: %w", err)
+			}
+
+			// Claims stored before the log existed are logged now
+			statusIndex, err := tlog.AppendClaim(context.Background(), claim)
+			if err != nil {
+				return err
+			}
+
+			if err := db.StoreRevocation(context.Background(), revocation, statusIndex); err != nil {
+				return err
+			}
+			if err := network.AddRevocation(revocation, statusIndex); err != nil {
+				return err
+			}
+
+			data, err := json.MarshalIndent(revocation, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	var rotationCmd = &cobra.Command{
+		Use:   "rotation [file|-]",
+		Short: "Record an agent key rotation",
+		Long: `Record a key rotation printed by "axios keys rotate". Claims the agent
+makes after the rotation takes effect must be signed, or delegated, by the
+new key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := auth.ValidateContext(ctx); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+
+			rotation, err := cli.ReadRotation(args[0])
+			if err != nil {
+				return err
+			}
+			if err := manager.AddRotation(rotation); err != nil {
+				return err
+			}
+			if err := db.StoreRotation(context.Background(), rotation); err != nil {
+				return err
+			}
+
+			fmt.Printf("Recorded key rotation for %s to %s\n", rotation.Issuer, rotation.Next)
+			return nil
+		},
+	}
+
+	var equivocationsCmd = &cobra.Command{
+		Use:   "equivocations",
+		Short: "List evidence of agents equivocating",
+		Long: `List the duplicate sequence numbers and forks found in agents' claim
+chains, followed by the gaps in the stored chains. With --report-as, each
+duplicate or fork is published as a claim against the agent, carrying the
+conflicting claims as evidence.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := auth.ValidateContext(ctx); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			reporter, _ := cmd.Flags().GetString("report-as")
+
+			equivocations, err := db.Equivocations(context.Background())
+			if err != nil {
+				return err
+			}
+
+			var output interface{} = equivocations
+			if reporter != "" {
+				if err := registerAgentKey(cmd, manager, reporter, logger); err != nil {
+					return err
+				}
+
+				reports := make([]*axiom.Claim, 0)
+				for _, equivocation := range equivocations {
+					if !equivocation.Conclusive() {
+						continue
+					}
+					report, err := manager.ReportEquivocation(context.Background(), reporter, equivocation)
+					if err != nil {
+						return fmt.Errorf("evidence against %s: %w", equivocation.Issuer, err)
+					}
+					if err := db.StoreClaim(context.Background(), report); err != nil {
+						return err
+					}
+					if err := network.AddClaim(report); err != nil {
+						return err
+					}
+					reports = append(reports, report)
+				}
+				output = reports
+			}
+
+			data, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	var statusListCmd = &cobra.Command{
+		Use:   "status-list",
+		Short: "Print the signed revocation status list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+
+			cred, err := status.Issue(network.StatusList(), id, nodeKey, time.Now())
+			if err != nil {
+				return err
+			}
+
+			data, err := json.MarshalIndent(cred, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
 			return nil
 		},
 	}
@@ -109,7 +567,10 @@ func main() {
 		Short: "Start the Axia webhook server",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			port, _ := cmd.Flags().GetInt("port")
-			srv := server.NewServer(port, manager, network, logger)
+			srv, err := server.NewServer(port, manager, network, db, tlog, tsa, nodeKey, logger)
+			if err != nil {
+				return err
+			}
 
 			// Handle graceful shutdown
 			done := make(chan os.Signal, 1)
@@ -176,9 +637,20 @@ func main() {
 				return fmt.Errorf("failed to query claims: %w", err)
 			}
 
+			// Revoked claims are left out, and their revocations published
+			revocations, err := db.Revocations(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to query revocations: %w", err)
+			}
+			revocationDocs := make([]*axiom.Revocation, 0, len(revocations))
+			for _, entry := range revocations {
+				revocationDocs = append(revocationDocs, entry.Revocation)
+			}
+
 			// Create graph representation
 			graphData := map[string]interface{}{
-				"claims": claims,
+				"claims":      claims,
+				"revocations": revocationDocs,
 				"metadata": map[string]interface{}{
 					"timestamp": time.Now(),
 					"version":   "1.0",
@@ -228,28 +700,183 @@ func main() {
 		},
 	}
 
+	var importCmd = &cobra.Command{
+		Use:   "import [ipfs-id]",
+		Short: "Import the claims of a trust graph from IPFS",
+		Long: `Fetch a trust graph from IPFS and store its claims. Claims may be JSON
+objects or compact JWTs; every claim is verified before any is stored.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ipfsClient := ipfs.NewTatumClient(os.Getenv("TATUM_API_KEY"), logger)
+			data, err := ipfsClient.GetGraph(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get graph from IPFS: %w", err)
+			}
+
+			var graphData struct {
+				Claims      json.RawMessage     `json:"claims"`
+				Revocations []*axiom.Revocation `json:"revocations"`
+			}
+			if err := json.Unmarshal(data, &graphData); err != nil {
+				return fmt.Errorf("invalid trust graph: %w", err)
+			}
+
+			claims, err := axiom.ReadClaims(bytes.NewReader(graphData.Claims))
+			if err != nil {
+				return err
+			}
+
+			for i, claim := range claims {
+				if err := manager.VerifyClaim(context.Background(), claim); err != nil {
+					return fmt.Errorf("claim %d failed verification: %w", i+1, err)
+				}
+			}
+
+			// Claims are stored as the network accepted them, with their
+			// timestamp
+			for _, claim := range claims {
+				if err := network.AddClaim(claim); err != nil {
+					return err
+				}
+				if err := db.StoreClaim(context.Background(), claim); err != nil {
+					return err
+				}
+			}
+
+			// Revocations are only honored when signed by the claim's issuer
+			for _, revocation := range graphData.Revocations {
+				claim, err := db.GetClaim(context.Background(), revocation.ClaimID)
+				if errors.Is(err, database.ErrClaimNotFound) {
+					logger.WithField("claim_id", revocation.ClaimID).Warn("Skipping revocation of unknown claim")
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if err := manager.VerifyRevocation(context.Background(), revocation, claim); err != nil {
+					return fmt.Errorf("revocation of %s failed verification: %w", revocation.ClaimID, err)
+				}
+
+				statusIndex, err := tlog.AppendClaim(context.Background(), claim)
+				if err != nil {
+					return err
+				}
+				if err := db.StoreRevocation(context.Background(), revocation, statusIndex); err != nil {
+					return err
+				}
+				if err := network.AddRevocation(revocation, statusIndex); err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("Imported %d claims and %d revocations from %s\n", len(claims), len(graphData.Revocations), args[0])
+			return nil
+		},
+	}
+
 	// Add flags
 	claimCmd.Flags().String("agent", "", "DID or URL of AI agent making the claim")
 	claimCmd.Flags().String("subject", "", "DID or URL of claim subject")
 	claimCmd.Flags().String("axiom", "", "Axiomatic statement being claimed")
 	claimCmd.Flags().Float64("confidence", 0.0, "Confidence score in range 0..1")
 	claimCmd.Flags().StringSlice("tags", []string{}, "Categorical tags for the claim")
+	claimCmd.Flags().String("canonicalization", crypto.CanonicalizationJCS, "Proof canonicalization (jcs, urdna2015)")
+	claimCmd.Flags().String("format", "json", "Output format (json, jwt)")
+	claimCmd.Flags().Duration("jwt-lifetime", 365*24*time.Hour, "Validity of JWT output, counted from issuance (0 for no expiry)")
+	claimCmd.Flags().StringSlice("cosigners", []string{}, "Agents who must co-sign the claim")
+	claimCmd.Flags().Int("threshold", 0, "Signatures required, the issuer's included (default all signers)")
+	claimCmd.Flags().StringSlice("recipients", []string{}, "did:key identifiers of the only agents able to read the claim")
+	claimCmd.Flags().StringSlice("conceal", []string{}, "Fields to selectively disclose (axiom, tags, confidenceValue)")
+	claimCmd.Flags().String("capability", "", "Capability chain delegating the agent's signing key (from axios keys delegate)")
 
 	truthCmd.Flags().String("observer", "", "Observer agent's perspective")
 	truthCmd.Flags().String("agent", "", "Filter by claim-making agent")
 	truthCmd.Flags().String("subject", "", "Filter by claim subject")
+	truthCmd.Flags().String("axiom", "", "Filter by axiomatic statement")
 	truthCmd.Flags().StringSlice("tags", []string{}, "Filter by categorical tags")
-	truthCmd.Flags().Int("depth", 3, "Search depth in trust network")
+	truthCmd.Flags().Int("depth", 3, "Trust hops from the observer whose agents' claims are included")
 	truthCmd.Flags().Float64("min-confidence", 0.0, "Minimum confidence threshold")
 	truthCmd.Flags().Float64("max-confidence", 1.0, "Maximum confidence threshold")
-	truthCmd.Flags().Bool("consensus", false, "Generate consensus analysis")
+	truthCmd.Flags().Bool("consensus", false, "Report the consensus on --subject or --axiom")
+	truthCmd.Flags().Bool("conflicts", false, "Report the disputes between the claims found")
 	truthCmd.Flags().Bool("decay", false, "Trust decay with network distance")
+	truthCmd.Flags().String("decay-model", trust.DecayExponential, "Trust decay model (exponential, linear, cutoff)")
+	truthCmd.Flags().Float64("decay-rate", trust.DefaultDecayRate, "Share of trust kept per hop by exponential decay")
+	truthCmd.Flags().String("t-norm", trust.TNormProduct, "Combination of trust along paths (product, minimum, lukasiewicz)")
+	truthCmd.Flags().Bool("include-revoked", false, "Include revoked claims in results")
+	truthCmd.Flags().String("rank", trust.RankPath, "Trust in agents from the observer (path, ppr, flow)")
+	truthCmd.Flags().StringSlice("seeds", []string{}, "Trusted roots of flow ranking (default the observer)")
+	truthCmd.Flags().IntSlice("capacities", graph.DefaultFlowCapacities, "Flow capacities by distance from the seeds")
+	truthCmd.Flags().String("fusion", subjective.FusionCumulative, "Fusion of the issuers' opinions in consensus reports (cumulative, averaging)")
+
+	reputationCmd.Flags().StringSlice("pretrusted", []string{}, "Agents trusted from the start")
+	reputationCmd.Flags().String("observer", "", "Rank by personalized PageRank from this agent")
+	reputationCmd.Flags().Int("top", 20, "Number of agents to list (0 for all)")
+
+	revokeCmd.Flags().String("reason", "", "Reason for the revocation")
+	equivocationsCmd.Flags().String("report-as", "", "Agent publishing claims against equivocating agents")
+	statusListCmd.Flags().String("id", "urn:axia:status:revocation", "Identifier of the status list credential")
 
 	serverCmd.Flags().Int("port", 8080, "Port to run the server on")
 
 	uploadCmd.Flags().StringToString("filter", nil, "Filters for claims to include in graph")
-	ipfsCmd.AddCommand(uploadCmd, getCmd)
+	ipfsCmd.AddCommand(uploadCmd, getCmd, importCmd)
+
+	rootCmd.AddCommand(claimCmd, truthCmd, reputationCmd, revokeCmd, statusListCmd, rotationCmd, equivocationsCmd, serverCmd, migrateCmd, ipfsCmd)
+	rootCmd.AddCommand(cli.GetKeysCmd(logger), cli.GetVerifyCmd(logger), cli.GetVCCmd(logger), cli.GetCoSignCmd(logger), cli.GetDiscloseCmd(logger))
+	rootCmd.AddCommand(cli.GetLogCmd(logger, func() *translog.Log { return tlog }))
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+} 
+
+// loadNodeKey reads the hex-encoded Ed25519 seed from AXIA_NODE_KEY
+func loadNodeKey() (*crypto.KeyPair, error) {
+	seed, err := hex.DecodeString(os.Getenv("AXIA_NODE_KEY"))
+	if err != nil {
+		return nil, fmt.Errorf("AXIA_NODE_KEY must be hex encoded: %w", err)
+	}
+	return crypto.NewKeyPairFromSeed(seed)
+}
+
+// registerCustodians binds the agents of the keystore's keys, retired ones
+// included, to those keys, so that claims they signed verify after the
+// keys are locked again
+func registerCustodians(cmd *cobra.Command, manager *axiom.Manager, logger *logrus.Logger) error {
+	ks, err := cli.OpenKeystore(cmd, logger)
+	if err != nil {
+		return err
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		publicKey, err := hex.DecodeString(key.PublicKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			logger.WithField("key", key.Name).Warn("Skipping keystore key with invalid public key")
+			continue
+		}
+		if !did.IsDID(key.Agent) {
+			manager.RegisterCustodian(key.Agent, publicKey)
+		}
+	}
+	return nil
+}
+
+// registerAgentKey unlocks the keystore key belonging to agent, if any, and
+// registers it as the agent's signer. Agents without a stored key fall back
+// to the node key.
+func registerAgentKey(cmd *cobra.Command, manager *axiom.Manager, agent string, logger *logrus.Logger) error {
+	signer, err := cli.UnlockAgentKey(cmd, logger, agent)
+	if errors.Is(err, keystore.ErrKeyNotFound) {
+		logger.WithField("agent", agent).Warn("No keystore key for agent, signing with node key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	rootCmd.AddCommand(claimCmd, truthCmd, serverCmd, migrateCmd, ipfsCmd)
-	rootCmd.Execute()
-} 
\ No newline at end of file
+	manager.RegisterSigner(agent, signer)
+	return nil
+}
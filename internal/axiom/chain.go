@@ -0,0 +1,361 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"axia/internal/crypto"
+	"axia/internal/delegation"
+	"axia/internal/did"
+)
+
+// Kinds of chain inconsistencies
+const (
+	// EquivocationDuplicate is two different claims with the same sequence
+	EquivocationDuplicate = "duplicate-sequence"
+
+	// EquivocationFork is a claim whose previous hash does not match the
+	// issuer's claim at the preceding sequence
+	EquivocationFork = "fork"
+
+	// EquivocationGap is a sequence number skipping claims not seen yet.
+	// Gaps are not proof of misbehavior, since the missing claims may
+	// simply not have arrived.
+	EquivocationGap = "gap"
+)
+
+// TagEquivocation tags claims reporting an equivocation
+const TagEquivocation = "equivocation"
+
+var (
+	ErrInvalidChainHash = errors.New("invalid chain hash")
+	ErrNotEquivocation  = errors.New("evidence does not show an equivocation")
+)
+
+// Equivocation is evidence that an issuer showed inconsistent histories.
+// Claims holds the conflicting claims in sequence order; for gaps it holds
+// the claim that follows the gap.
+type Equivocation struct {
+	Kind     string   `json:"kind"`
+	Issuer   string   `json:"issuer"`
+	Sequence uint64   `json:"sequence"`
+	Claims   []*Claim `json:"claims"`
+}
+
+// ChainHash returns the multibase-encoded multihash of the claim that the
+// issuer's next claim names as its previous claim. The proof is left out,
+// so a claim hashes the same as JSON or JWT and whatever co-signatures it
+// has collected.
+func ChainHash(claim *Claim) (string, error) {
+	return chainHash(crypto.NewProofGenerator(), claim)
+}
+
+func chainHash(proofGen *crypto.ProofGenerator, claim *Claim) (string, error) {
+	payload, err := unprovenClaim(claim)
+	if err != nil {
+		return "", err
+	}
+	proof, err := proofGen.GenerateProof(payload)
+	if err != nil {
+		return "", err
+	}
+	return proof.Hash, nil
+}
+
+// Follows reports whether next names claim as its previous claim. The hash
+// is recomputed with the algorithm next's previous hash was made with.
+func Follows(next, claim *Claim) (bool, error) {
+	if next.Issuer != claim.Issuer || next.Sequence != claim.Sequence+1 {
+		return false, nil
+	}
+
+	mh, err := crypto.DecodeMultibase(next.Previous)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidChainHash, err)
+	}
+	algorithm, _, err := crypto.DecodeMultihash(mh)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidChainHash, err)
+	}
+
+	proofGen := crypto.NewProofGenerator()
+	if err := proofGen.SetHashAlgorithm(algorithm.Name); err != nil {
+		return false, err
+	}
+	hash, err := chainHash(proofGen, claim)
+	if err != nil {
+		return false, err
+	}
+	return hash == next.Previous, nil
+}
+
+// sameClaim reports whether a and b are the same claim, possibly with
+// different proofs
+func sameClaim(a, b *Claim) bool {
+	hashA, errA := ChainHash(a)
+	hashB, errB := ChainHash(b)
+	return errA == nil && errB == nil && hashA == hashB
+}
+
+// CheckChain compares a claim with known claims and returns the duplicate
+// sequences and forks it reveals in its issuer's chain. Unsequenced claims
+// are never flagged.
+func CheckChain(claim *Claim, known []*Claim) []*Equivocation {
+	if claim.Sequence == 0 {
+		return nil
+	}
+
+	var found []*Equivocation
+	for _, other := range known {
+		if other.Issuer != claim.Issuer || other.Sequence == 0 || sameClaim(other, claim) {
+			continue
+		}
+
+		switch other.Sequence {
+		case claim.Sequence:
+			found = append(found, &Equivocation{
+				Kind:     EquivocationDuplicate,
+				Issuer:   claim.Issuer,
+				Sequence: claim.Sequence,
+				Claims:   []*Claim{other, claim},
+			})
+		case claim.Sequence - 1:
+			if ok, err := Follows(claim, other); err != nil || !ok {
+				found = append(found, &Equivocation{
+					Kind:     EquivocationFork,
+					Issuer:   claim.Issuer,
+					Sequence: claim.Sequence,
+					Claims:   []*Claim{other, claim},
+				})
+			}
+		case claim.Sequence + 1:
+			if ok, err := Follows(other, claim); err != nil || !ok {
+				found = append(found, &Equivocation{
+					Kind:     EquivocationFork,
+					Issuer:   claim.Issuer,
+					Sequence: other.Sequence,
+					Claims:   []*Claim{claim, other},
+				})
+			}
+		}
+	}
+	return found
+}
+
+// ChainGaps returns the gaps in the issuers' chains formed by claims. Each
+// gap names the first missing sequence and the claim following the gap.
+func ChainGaps(claims []*Claim) []*Equivocation {
+	byIssuer := make(map[string][]*Claim)
+	for _, claim := range claims {
+		if claim.Sequence > 0 {
+			byIssuer[claim.Issuer] = append(byIssuer[claim.Issuer], claim)
+		}
+	}
+
+	var gaps []*Equivocation
+	for issuer, chain := range byIssuer {
+		sort.Slice(chain, func(i, j int) bool { return chain[i].Sequence < chain[j].Sequence })
+
+		var latest uint64
+		for _, claim := range chain {
+			if claim.Sequence > latest+1 {
+				gaps = append(gaps, &Equivocation{
+					Kind:     EquivocationGap,
+					Issuer:   issuer,
+					Sequence: latest + 1,
+					Claims:   []*Claim{claim},
+				})
+			}
+			latest = claim.Sequence
+		}
+	}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		if gaps[i].Issuer != gaps[j].Issuer {
+			return gaps[i].Issuer < gaps[j].Issuer
+		}
+		return gaps[i].Sequence < gaps[j].Sequence
+	})
+	return gaps
+}
+
+// Conclusive reports whether the equivocation proves misbehavior by the
+// issuer, i.e. it is not a gap
+func (e *Equivocation) Conclusive() bool {
+	return e.Kind == EquivocationDuplicate || e.Kind == EquivocationFork
+}
+
+// Verify checks that the evidence proves an equivocation: both claims are
+// validly issued by the issuer under the same root key and contradict each
+// other
+func (e *Equivocation) Verify(ctx context.Context, resolver did.Resolver) error {
+	return e.verify(ctx, resolver, nil)
+}
+
+// VerifyEquivocation checks the evidence like Equivocation.Verify, also
+// accepting claims signed under successive root keys of the issuer's
+// recorded rotations
+func (m *Manager) VerifyEquivocation(ctx context.Context, e *Equivocation) error {
+	return e.verify(ctx, m.custodians, m.keys)
+}
+
+func (e *Equivocation) verify(ctx context.Context, resolver did.Resolver, keys *delegation.History) error {
+	if !e.Conclusive() || len(e.Claims) != 2 {
+		return fmt.Errorf("%w: %s with %d claims", ErrNotEquivocation, e.Kind, len(e.Claims))
+	}
+	for _, claim := range e.Claims {
+		if claim.Issuer != e.Issuer {
+			return fmt.Errorf("%w: claim issued by %s", ErrNotEquivocation, claim.Issuer)
+		}
+		if err := VerifyClaim(ctx, resolver, claim); err != nil {
+			return err
+		}
+	}
+
+	first, second := e.Claims[0], e.Claims[1]
+	if sameClaim(first, second) {
+		return fmt.Errorf("%w: claims are identical", ErrNotEquivocation)
+	}
+	if err := sameRootKey(first, second, keys); err != nil {
+		return err
+	}
+	switch e.Kind {
+	case EquivocationDuplicate:
+		if first.Sequence != e.Sequence || second.Sequence != e.Sequence {
+			return fmt.Errorf("%w: sequences differ", ErrNotEquivocation)
+		}
+	case EquivocationFork:
+		if first.Sequence+1 != e.Sequence || second.Sequence != e.Sequence {
+			return fmt.Errorf("%w: claims are not consecutive", ErrNotEquivocation)
+		}
+		if ok, err := Follows(second, first); err == nil && ok {
+			return fmt.Errorf("%w: claims are linked", ErrNotEquivocation)
+		}
+	}
+	return nil
+}
+
+// sameRootKey checks that both claims of an issuer were signed under its
+// root key: the same key, or the keys its rotation history held when each
+// claim was issued. Claims under unrelated keys, such as keys of different
+// custodians or DID verification methods, may come from different signers
+// and prove nothing against either.
+func sameRootKey(first, second *Claim, keys *delegation.History) error {
+	firstKey, err := RootKey(first)
+	if err != nil {
+		return err
+	}
+	secondKey, err := RootKey(second)
+	if err != nil {
+		return err
+	}
+	if firstKey.Equal(secondKey) {
+		return nil
+	}
+
+	if keys != nil {
+		if _, ok := keys.KeyAt(first.Issuer, first.Issued); ok &&
+			keys.CheckKey(first.Issuer, firstKey, first.Issued) == nil &&
+			keys.CheckKey(second.Issuer, secondKey, second.Issued) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: claims are signed by different keys", ErrNotEquivocation)
+}
+
+// ChainStore persists the head of each agent's claim chain, so chains
+// continue across restarts and claims that are never stored, such as
+// claims awaiting co-signatures
+type ChainStore interface {
+	// ChainHead returns the sequence and chain hash of the agent's latest
+	// claim, or zero values if it has none
+	ChainHead(ctx context.Context, agent string) (uint64, string, error)
+	SetChainHead(ctx context.Context, agent string, sequence uint64, hash string) error
+}
+
+// chains tracks the head of each agent's claim chain
+type chains struct {
+	mu    sync.Mutex
+	store ChainStore
+	heads map[string]chainHead
+}
+
+// chainHead is the latest claim issued for an agent
+type chainHead struct {
+	sequence uint64
+	hash     string
+}
+
+// head returns the agent's chain head. The caller holds c.mu.
+func (c *chains) head(ctx context.Context, agent string) (chainHead, error) {
+	if c.store == nil {
+		return c.heads[agent], nil
+	}
+	sequence, hash, err := c.store.ChainHead(ctx, agent)
+	if err != nil {
+		return chainHead{}, fmt.Errorf("failed to load claim chain of %s: %w", agent, err)
+	}
+	return chainHead{sequence: sequence, hash: hash}, nil
+}
+
+// advance makes claim the head of its issuer's chain. The caller holds
+// c.mu.
+func (c *chains) advance(ctx context.Context, claim *Claim) error {
+	hash, err := ChainHash(claim)
+	if err != nil {
+		return err
+	}
+	if c.store != nil {
+		if err := c.store.SetChainHead(ctx, claim.Issuer, claim.Sequence, hash); err != nil {
+			return fmt.Errorf("failed to save claim chain of %s: %w", claim.Issuer, err)
+		}
+	}
+	c.heads[claim.Issuer] = chainHead{sequence: claim.Sequence, hash: hash}
+	return nil
+}
+
+// SetChainStore sets the store chain heads are kept in. Without one,
+// chains start over whenever the manager is created.
+func (m *Manager) SetChainStore(store ChainStore) {
+	m.chains.mu.Lock()
+	defer m.chains.mu.Unlock()
+	m.chains.store = store
+}
+
+// ReportEquivocation issues a claim by reporter stating that the issuer
+// of the evidence equivocated. The claim carries the conflicting claims
+// as evidence, so anyone can check it with VerifyEquivocationClaim.
+func (m *Manager) ReportEquivocation(ctx context.Context, reporter string, e *Equivocation) (*Claim, error) {
+	if err := m.VerifyEquivocation(ctx, e); err != nil {
+		return nil, err
+	}
+
+	body := newBody(reporter, e.Issuer,
+		fmt.Sprintf("Equivocated: %s at sequence %d", e.Kind, e.Sequence),
+		1, []string{TagEquivocation})
+	body.Evidence = e.Claims
+	return m.createClaim(body, nil, nil)
+}
+
+// VerifyEquivocationClaim checks that an equivocation claim's evidence
+// proves that its subject equivocated
+func VerifyEquivocationClaim(ctx context.Context, resolver did.Resolver, claim *Claim) error {
+	evidence := claim.ClaimBody.Evidence
+	if len(evidence) != 2 {
+		return fmt.Errorf("%w: claim carries %d evidence claims", ErrNotEquivocation, len(evidence))
+	}
+
+	e := &Equivocation{
+		Kind:     EquivocationDuplicate,
+		Issuer:   claim.ClaimBody.Subject,
+		Sequence: evidence[1].Sequence,
+		Claims:   evidence,
+	}
+	if evidence[0].Sequence != evidence[1].Sequence {
+		e.Kind = EquivocationFork
+	}
+	return e.Verify(ctx, resolver)
+}
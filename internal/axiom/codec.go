@@ -0,0 +1,107 @@
+package axiom
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadClaims decodes claims from r. The input may be a single claim object,
+// a JSON array of claims, a stream of claims with one object per line, or
+// whitespace-separated compact JWTs. Array and stream elements may also be
+// JWTs given as JSON strings.
+func ReadClaims(r io.Reader) ([]*Claim, error) {
+	br := bufio.NewReader(r)
+
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claims: %w", err)
+	}
+
+	if first != '[' && first != '{' && first != '"' {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read claims: %w", err)
+		}
+
+		claims := make([]*Claim, 0)
+		for _, token := range strings.Fields(string(data)) {
+			claim, err := DecodeJWT(token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode claim %d: %w", len(claims)+1, err)
+			}
+			claims = append(claims, claim)
+		}
+		return claims, nil
+	}
+
+	dec := json.NewDecoder(br)
+
+	if first == '[' {
+		var raw []json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to decode claim array: %w", err)
+		}
+
+		claims := make([]*Claim, 0, len(raw))
+		for i, msg := range raw {
+			claim, err := DecodeClaim(msg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode claim %d: %w", i+1, err)
+			}
+			claims = append(claims, claim)
+		}
+		return claims, nil
+	}
+
+	claims := make([]*Claim, 0)
+	for {
+		var msg json.RawMessage
+		err := dec.Decode(&msg)
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			var claim *Claim
+			if claim, err = DecodeClaim(msg); err == nil {
+				claims = append(claims, claim)
+				continue
+			}
+		}
+		return nil, fmt.Errorf("failed to decode claim %d: %w", len(claims)+1, err)
+	}
+	return claims, nil
+}
+
+// DecodeClaim decodes a JSON claim object or a JWT given as a JSON string
+func DecodeClaim(data json.RawMessage) (*Claim, error) {
+	var token string
+	if err := json.Unmarshal(data, &token); err == nil {
+		return DecodeJWT(token)
+	}
+
+	claim := &Claim{}
+	if err := json.Unmarshal(data, claim); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
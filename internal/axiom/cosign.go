@@ -0,0 +1,195 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidPolicy     = errors.New("invalid co-signing policy")
+	ErrNotPolicySigner   = errors.New("agent is not a signer under the claim's policy")
+	ErrDuplicateSigner   = errors.New("agent has already signed the claim")
+	ErrThresholdNotMet   = errors.New("claim does not have enough co-signatures")
+	ErrCoSignatureBroken = errors.New("co-signature verification failed")
+)
+
+// ThresholdPolicy requires Threshold of the listed signers, the issuer
+// included, to sign a claim before it takes effect
+type ThresholdPolicy struct {
+	Threshold int      `json:"threshold"`
+	Signers   []string `json:"signers"`
+}
+
+// CoSignature is an additional agent's signature over a claim. Co-signers
+// sign the claim as issued, so signatures can be added in any order.
+type CoSignature struct {
+	Signer           string    `json:"signer"`
+	Created          time.Time `json:"created"`
+	Verifier         Verifier  `json:"verifier"`
+	Canonicalization string    `json:"canonicalization,omitempty"`
+	ProofValue       string    `json:"proofValue"`
+}
+
+// coSigningPayload is the document covered by a co-signature: the claim
+// as signed by its issuer and the co-signature's own metadata
+type coSigningPayload struct {
+	Claim       *Claim       `json:"claim"`
+	CoSignature *CoSignature `json:"coSignature"`
+}
+
+func (c *Claim) coSigningPayload(cosignature *CoSignature) *coSigningPayload {
+	metadata := *cosignature
+	metadata.ProofValue = ""
+	return &coSigningPayload{
+		Claim:       c.signingPayload(),
+		CoSignature: &metadata,
+	}
+}
+
+// Validate checks that the policy can be satisfied
+func (p *ThresholdPolicy) Validate() error {
+	if p.Threshold < 1 || p.Threshold > len(p.Signers) {
+		return fmt.Errorf("%w: threshold %d of %d signers", ErrInvalidPolicy, p.Threshold, len(p.Signers))
+	}
+	seen := make(map[string]bool, len(p.Signers))
+	for _, signer := range p.Signers {
+		if seen[signer] {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidPolicy, signer)
+		}
+		seen[signer] = true
+	}
+	return nil
+}
+
+// Signers returns the issuer followed by the co-signers of the claim
+func (c *Claim) Signers() []string {
+	signers := []string{c.Issuer}
+	for _, cosignature := range c.Proof.CoSignatures {
+		signers = append(signers, cosignature.Signer)
+	}
+	return signers
+}
+
+// ThresholdMet reports whether a claim is complete: it has no policy, or
+// enough of the policy's signers have signed it. Signatures are assumed
+// to have been verified.
+func ThresholdMet(claim *Claim) bool {
+	policy := claim.Proof.Policy
+	if policy == nil {
+		return true
+	}
+
+	count := 0
+	for _, signer := range claim.Signers() {
+		for _, allowed := range policy.Signers {
+			if signer == allowed {
+				count++
+				break
+			}
+		}
+	}
+	return count >= policy.Threshold
+}
+
+// CoSign adds agent's signature to the claim
+func (m *Manager) CoSign(claim *Claim, agent string) error {
+	for _, signer := range claim.Signers() {
+		if signer == agent {
+			return fmt.Errorf("%w: %s", ErrDuplicateSigner, agent)
+		}
+	}
+	if policy := claim.Proof.Policy; policy != nil {
+		allowed := false
+		for _, signer := range policy.Signers {
+			allowed = allowed || signer == agent
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %s", ErrNotPolicySigner, agent)
+		}
+	}
+
+	signer, err := m.signerFor(agent)
+	if err != nil {
+		return err
+	}
+	if err := did.VerifyKey(context.Background(), m.custodians, agent, signer.PublicKey()); err != nil {
+		return fmt.Errorf("cannot co-sign as %s: %w", agent, err)
+	}
+
+	cosignature := CoSignature{
+		Signer:           agent,
+		Created:          time.Now().UTC().Truncate(time.Second),
+		Verifier:         Verifier{ID: KeyID(signer.PublicKey())},
+		Canonicalization: m.proofGen.Canonicalization(),
+	}
+
+	proof, err := m.proofGen.SignProof(claim.coSigningPayload(&cosignature), signer)
+	if err != nil {
+		return fmt.Errorf("failed to co-sign claim: %w", err)
+	}
+	cosignature.ProofValue = proof.Signature
+
+	claim.Proof.CoSignatures = append(claim.Proof.CoSignatures, cosignature)
+
+	m.logger.WithFields(logrus.Fields{
+		"claim_id": ClaimID(claim),
+		"signer":   agent,
+	}).Info("Co-signed claim")
+	return nil
+}
+
+// verifyCoSignatures checks the signature of every co-signer
+func verifyCoSignatures(claim *Claim) error {
+	if policy := claim.Proof.Policy; policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	seen := map[string]bool{claim.Issuer: true}
+	for i := range claim.Proof.CoSignatures {
+		cosignature := &claim.Proof.CoSignatures[i]
+		if seen[cosignature.Signer] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSigner, cosignature.Signer)
+		}
+		seen[cosignature.Signer] = true
+
+		publicKey, err := ParseKeyID(cosignature.Verifier.ID)
+		if err != nil {
+			return err
+		}
+
+		proofGen := crypto.NewProofGenerator()
+		if err := proofGen.SetCanonicalization(cosignature.Canonicalization); err != nil {
+			return err
+		}
+		err = proofGen.VerifySignature(claim.coSigningPayload(cosignature), cosignature.ProofValue, publicKey)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCoSignatureBroken, cosignature.Signer, err)
+		}
+	}
+	return nil
+}
+
+// verifyCoSigners checks that co-signers resolve to their keys. Like
+// issuers, co-signers that are not DIDs must have a custodian, so that no
+// co-signature counts towards a threshold or vouches for the subject
+// without its signer's key.
+func verifyCoSigners(ctx context.Context, resolver did.Resolver, claim *Claim) error {
+	for _, cosignature := range claim.Proof.CoSignatures {
+		publicKey, err := ParseKeyID(cosignature.Verifier.ID)
+		if err != nil {
+			return err
+		}
+		if err := did.VerifyKey(ctx, resolver, cosignature.Signer, publicKey); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCoSignatureBroken, cosignature.Signer, err)
+		}
+	}
+	return nil
+}
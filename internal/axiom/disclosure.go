@@ -0,0 +1,286 @@
+package axiom
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"axia/internal/crypto"
+)
+
+// Body fields that can be selectively disclosed
+const (
+	DisclosableAxiom      = "axiom"
+	DisclosableTags       = "tags"
+	DisclosableConfidence = "confidenceValue"
+)
+
+var (
+	ErrNotDisclosable     = errors.New("field cannot be selectively disclosed")
+	ErrInvalidDisclosure  = errors.New("invalid disclosure")
+	ErrSelectiveClaim     = errors.New("claim has selectively disclosed fields")
+	ErrDisclosureConflict = errors.New("disclosed field is also present in the signed claim")
+)
+
+// IsSelective reports whether the claim body has concealed fields
+func (c *Claim) IsSelective() bool {
+	return len(c.ClaimBody.SD) > 0
+}
+
+// Reveals reports whether a claim, or a Disclose view of it, shows the
+// named field: the field is disclosed, or was signed in the clear. An empty
+// field of a claim with concealed fields counts as concealed.
+func Reveals(claim *Claim, field string) bool {
+	if !claim.IsSelective() {
+		return true
+	}
+	for _, encoded := range claim.Proof.Disclosures {
+		if d, err := ParseDisclosure(encoded); err == nil && d.Name == field {
+			return true
+		}
+	}
+
+	switch field {
+	case DisclosableAxiom:
+		return claim.ClaimBody.Rating.Axiom != ""
+	case DisclosableTags:
+		return len(claim.ClaimBody.Tags) > 0
+	case DisclosableConfidence:
+		return claim.ClaimBody.Rating.ConfidenceValue != 0
+	}
+	return true
+}
+
+// Disclosure reveals one concealed body field. Its encoding, as in SD-JWT,
+// is the base64url JSON array [salt, name, value], and the claim body
+// lists the digest of every encoded disclosure in _sd.
+type Disclosure struct {
+	Salt  string
+	Name  string
+	Value json.RawMessage
+}
+
+// Encode returns the base64url encoding of the disclosure
+func (d *Disclosure) Encode() (string, error) {
+	data, err := json.Marshal([]interface{}{d.Salt, d.Name, d.Value})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ParseDisclosure decodes an encoded disclosure
+func ParseDisclosure(encoded string) (*Disclosure, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDisclosure, err)
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(data, &parts); err != nil || len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a [salt, name, value] array", ErrInvalidDisclosure)
+	}
+	d := &Disclosure{Value: parts[2]}
+	if err := json.Unmarshal(parts[0], &d.Salt); err != nil {
+		return nil, fmt.Errorf("%w: salt: %v", ErrInvalidDisclosure, err)
+	}
+	if err := json.Unmarshal(parts[1], &d.Name); err != nil {
+		return nil, fmt.Errorf("%w: name: %v", ErrInvalidDisclosure, err)
+	}
+	return d, nil
+}
+
+// disclosureDigest returns the digest of an encoded disclosure as listed in
+// _sd, hashed with the given algorithm
+func disclosureDigest(algorithm *crypto.HashAlgorithm, encoded string) string {
+	return crypto.EncodeMultibase(algorithm.Sum([]byte(encoded)))
+}
+
+// concealFields removes the named fields from body, lists their digests in
+// _sd and returns the encoded disclosures
+func concealFields(body *Body, fields []string) ([]string, error) {
+	algorithm, err := crypto.LookupHash(crypto.NewProofGenerator().HashAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	disclosures := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s concealed twice", ErrNotDisclosable, name)
+		}
+		seen[name] = true
+
+		var value interface{}
+		switch name {
+		case DisclosableAxiom:
+			value, body.Rating.Axiom = body.Rating.Axiom, ""
+		case DisclosableTags:
+			value, body.Tags = body.Tags, nil
+		case DisclosableConfidence:
+			value, body.Rating.ConfidenceValue = body.Rating.ConfidenceValue, 0
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNotDisclosable, name)
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+
+		d := &Disclosure{Salt: base64.RawURLEncoding.EncodeToString(salt), Name: name, Value: data}
+		encoded, err := d.Encode()
+		if err != nil {
+			return nil, err
+		}
+		disclosures = append(disclosures, encoded)
+		body.SD = append(body.SD, disclosureDigest(algorithm, encoded))
+	}
+	return disclosures, nil
+}
+
+// CreateSelectiveClaim creates a claim whose concealed fields are only
+// revealed by the disclosures returned with it in its proof. Holders choose
+// which disclosures to present with Present.
+func (m *Manager) CreateSelectiveClaim(agent, subject, axiom string, confidence float64, tags []string, concealed []string) (*Claim, error) {
+	body := newBody(agent, subject, axiom, confidence, tags)
+	disclosures, err := concealFields(&body, concealed)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := m.createClaim(body, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	claim.Proof.Disclosures = disclosures
+	return claim, nil
+}
+
+// Present returns a copy of the claim carrying only the disclosures of the
+// named fields. The copy still verifies against the issuer's signature.
+func Present(claim *Claim, fields []string) (*Claim, error) {
+	presented := *claim
+	presented.Proof.Disclosures = nil
+
+	for _, name := range fields {
+		found := false
+		for _, encoded := range claim.Proof.Disclosures {
+			d, err := ParseDisclosure(encoded)
+			if err != nil {
+				return nil, err
+			}
+			if d.Name == name {
+				presented.Proof.Disclosures = append(presented.Proof.Disclosures, encoded)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: no disclosure for %s", ErrNotDisclosable, name)
+		}
+	}
+	return &presented, nil
+}
+
+// Disclose returns a copy of the claim with its disclosed fields filled in.
+// Concealed fields that are not disclosed keep their zero value. The copy
+// is a view for display and queries; its proof can only be checked on the
+// original claim.
+func Disclose(claim *Claim) (*Claim, error) {
+	if len(claim.Proof.Disclosures) == 0 {
+		return claim, nil
+	}
+
+	disclosed := *claim
+	body := &disclosed.ClaimBody
+	body.Tags = append([]string(nil), body.Tags...)
+	for _, encoded := range claim.Proof.Disclosures {
+		d, err := ParseDisclosure(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		var target interface{}
+		switch d.Name {
+		case DisclosableAxiom:
+			target = &body.Rating.Axiom
+		case DisclosableTags:
+			target = &body.Tags
+		case DisclosableConfidence:
+			target = &body.Rating.ConfidenceValue
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNotDisclosable, d.Name)
+		}
+		if err := json.Unmarshal(d.Value, target); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDisclosure, d.Name, err)
+		}
+	}
+	return &disclosed, nil
+}
+
+// verifyDisclosures checks that every disclosure of a claim is listed in
+// its _sd digests, discloses an allowed field at most once, and does not
+// contradict a field the issuer signed in the clear
+func verifyDisclosures(claim *Claim) error {
+	if len(claim.Proof.Disclosures) == 0 {
+		return nil
+	}
+
+	// Digests name their algorithm, normally the same for all of them
+	digests := make(map[string]bool, len(claim.ClaimBody.SD))
+	algorithms := make(map[string]*crypto.HashAlgorithm)
+	for _, digest := range claim.ClaimBody.SD {
+		digests[digest] = true
+		if mh, err := crypto.DecodeMultibase(digest); err == nil {
+			if algorithm, _, err := crypto.DecodeMultihash(mh); err == nil {
+				algorithms[algorithm.Name] = algorithm
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, encoded := range claim.Proof.Disclosures {
+		listed := false
+		for _, algorithm := range algorithms {
+			if digests[disclosureDigest(algorithm, encoded)] {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			return fmt.Errorf("%w: digest not listed in the claim", ErrInvalidDisclosure)
+		}
+
+		d, err := ParseDisclosure(encoded)
+		if err != nil {
+			return err
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("%w: %s disclosed twice", ErrInvalidDisclosure, d.Name)
+		}
+		seen[d.Name] = true
+
+		var concealed bool
+		switch d.Name {
+		case DisclosableAxiom:
+			concealed = claim.ClaimBody.Rating.Axiom == ""
+		case DisclosableTags:
+			concealed = len(claim.ClaimBody.Tags) == 0
+		case DisclosableConfidence:
+			concealed = claim.ClaimBody.Rating.ConfidenceValue == 0
+		default:
+			return fmt.Errorf("%w: %s", ErrNotDisclosable, d.Name)
+		}
+		if !concealed {
+			return fmt.Errorf("%w: %s", ErrDisclosureConflict, d.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package axiom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"axia/internal/canon"
+	"axia/internal/jose"
+)
+
+const (
+	// ProofTypeJWT marks claims decoded from a JWT; the proof value holds
+	// the compact JWS
+	ProofTypeJWT = "JsonWebSignature"
+
+	// JWTType is the typ header of claim JWTs
+	JWTType = "JWT"
+)
+
+var (
+	ErrJWTExpired   = errors.New("claim JWT has expired")
+	ErrJWTMismatch  = errors.New("claim does not match its JWT")
+	ErrJWTProofData = errors.New("capability chains and co-signing policies cannot be encoded as JWTs")
+)
+
+func init() {
+	RegisterProofVerifier(ProofTypeJWT, verifyJWTProof)
+}
+
+// jwtPayload is the JWT claims set wrapping an axiomatic claim
+type jwtPayload struct {
+	Issuer   string          `json:"iss"`
+	Subject  string          `json:"sub"`
+	IssuedAt int64           `json:"iat"`
+	Expires  int64           `json:"exp,omitempty"`
+	ID       string          `json:"jti,omitempty"`
+	Claim    json.RawMessage `json:"claim"`
+}
+
+// EncodeJWT wraps the claim, without its proof, in a compact JWS signed by
+// signer. iss, sub and iat are taken from the claim; exp is iat plus
+// lifetime, or omitted if lifetime is zero. Disclosures of a selectively
+// disclosed claim follow the JWS as in SD-JWT: jws~disclosure~...~
+func EncodeJWT(claim *Claim, signer jose.Signer, lifetime time.Duration) (string, error) {
+	// The JWT replaces the proof, which would drop these
+	if len(claim.Proof.CapabilityChain) > 0 || claim.Proof.Policy != nil {
+		return "", ErrJWTProofData
+	}
+	body, err := unprovenClaim(claim)
+	if err != nil {
+		return "", err
+	}
+
+	payload := jwtPayload{
+		Issuer:   claim.Issuer,
+		Subject:  claim.ClaimBody.Subject,
+		IssuedAt: claim.Issued.Unix(),
+		ID:       claim.ID,
+		Claim:    body,
+	}
+	if lifetime > 0 {
+		payload.Expires = claim.Issued.Add(lifetime).Unix()
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	token, err := jose.Sign(data, JWTType, signer)
+	if err != nil {
+		return "", err
+	}
+	if len(claim.Proof.Disclosures) > 0 {
+		token += "~" + strings.Join(claim.Proof.Disclosures, "~") + "~"
+	}
+	return token, nil
+}
+
+// DecodeJWT unwraps a claim JWT. The returned claim carries the JWS as its
+// proof value and any trailing disclosures as its disclosures; the
+// signature is checked by VerifyProof, not here.
+func DecodeJWT(token string) (*Claim, error) {
+	var disclosures []string
+	if parts := strings.Split(token, "~"); len(parts) > 1 {
+		token = parts[0]
+		for _, disclosure := range parts[1:] {
+			if disclosure != "" {
+				disclosures = append(disclosures, disclosure)
+			}
+		}
+	}
+
+	jws, err := jose.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := parseJWTPayload(jws)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &Claim{}
+	if err := json.Unmarshal(payload.Claim, claim); err != nil {
+		return nil, fmt.Errorf("invalid claim in JWT: %w", err)
+	}
+
+	claim.Proof = Proof{
+		Type:        ProofTypeJWT,
+		Created:     time.Unix(payload.IssuedAt, 0).UTC(),
+		Verifier:    Verifier{ID: jws.Header.KeyID},
+		ProofValue:  token,
+		Disclosures: disclosures,
+	}
+	return claim, nil
+}
+
+// verifyJWTProof checks the JWS held in the proof value and that it wraps
+// exactly this claim
+func verifyJWTProof(claim *Claim) error {
+	jws, err := jose.Parse(claim.Proof.ProofValue)
+	if err != nil {
+		return err
+	}
+	if jws.Header.KeyID != claim.Proof.Verifier.ID {
+		return fmt.Errorf("%w: kid %s", ErrJWTMismatch, jws.Header.KeyID)
+	}
+	if err := jws.Verify(); err != nil {
+		return err
+	}
+
+	payload, err := parseJWTPayload(jws)
+	if err != nil {
+		return err
+	}
+	if payload.Expires != 0 && time.Now().Unix() >= payload.Expires {
+		return ErrJWTExpired
+	}
+	if payload.Issuer != claim.Issuer || payload.Subject != claim.ClaimBody.Subject {
+		return fmt.Errorf("%w: iss or sub", ErrJWTMismatch)
+	}
+
+	// Compare decoded claims, so equivalent encodings of a value such as
+	// a string confidence do not count as a mismatch
+	signed := &Claim{}
+	if err := json.Unmarshal(payload.Claim, signed); err != nil {
+		return fmt.Errorf("invalid claim in JWT: %w", err)
+	}
+	want, err := canonicalClaim(signed)
+	if err != nil {
+		return err
+	}
+	got, err := canonicalClaim(claim)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(want, got) {
+		return ErrJWTMismatch
+	}
+	return nil
+}
+
+func parseJWTPayload(jws *jose.JWS) (*jwtPayload, error) {
+	if jws.Header.Type != "" && jws.Header.Type != JWTType {
+		return nil, fmt.Errorf("%w: typ %s", jose.ErrMalformed, jws.Header.Type)
+	}
+
+	payload := &jwtPayload{}
+	if err := json.Unmarshal(jws.Payload, payload); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", jose.ErrMalformed, err)
+	}
+	if len(payload.Claim) == 0 {
+		return nil, fmt.Errorf("%w: payload has no claim", jose.ErrMalformed)
+	}
+	return payload, nil
+}
+
+func canonicalClaim(claim *Claim) ([]byte, error) {
+	body, err := unprovenClaim(claim)
+	if err != nil {
+		return nil, err
+	}
+	return canon.JCS(body)
+}
+
+// unprovenClaim encodes the claim with its proof member removed
+func unprovenClaim(claim *Claim) (json.RawMessage, error) {
+	data, err := json.Marshal(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "proof")
+	return json.Marshal(fields)
+}
@@ -0,0 +1,90 @@
+package axiom
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"axia/internal/did"
+	"axia/internal/jose"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTRoundTrip(t *testing.T) {
+	manager, key := newTestManager(t)
+
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, []string{"physics"})
+	assert.NoError(t, err)
+
+	token, err := manager.EncodeJWT(claim, time.Hour)
+	assert.NoError(t, err)
+
+	claims, err := ReadClaims(strings.NewReader(token + "\n"))
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+
+	decoded := claims[0]
+	assert.Equal(t, ProofTypeJWT, decoded.Proof.Type)
+	assert.Equal(t, did.VerificationMethodID(did.FromPublicKey(key.PublicKey())), decoded.Proof.Verifier.ID)
+	assert.Equal(t, claim.ClaimBody, decoded.ClaimBody)
+	assert.NoError(t, VerifyProof(decoded))
+
+	decoded.ClaimBody.Rating.ConfidenceValue = 0.1
+	assert.ErrorIs(t, VerifyProof(decoded), ErrProofVerification)
+
+	// JWTs expire relative to the claim's issuance
+	expired, err := manager.EncodeJWT(claim, time.Nanosecond)
+	assert.NoError(t, err)
+	decoded, err = DecodeJWT(expired)
+	assert.NoError(t, err)
+	err = VerifyProof(decoded)
+	assert.ErrorIs(t, err, ErrProofVerification)
+	assert.ErrorContains(t, err, ErrJWTExpired.Error())
+}
+
+func TestJWTES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signer, err := jose.NewES256Signer(key)
+	assert.NoError(t, err)
+
+	issuer := did.FromP256PublicKey(&key.PublicKey)
+	assert.True(t, strings.HasPrefix(issuer, "did:key:zDn"))
+
+	claim := &Claim{
+		Context: "https://schema.axios.ai/AxiomaticClaim.jsonld",
+		Type:    "AxiomaticClaim",
+		Issuer:  issuer,
+		Issued:  time.Now().UTC().Truncate(time.Second),
+		ClaimBody: Body{
+			Subject: "did:fact:sky",
+			Agent:   issuer,
+			Rating:  AxiomRating{ConfidenceValue: 0.8, Axiom: "Sky is blue"},
+		},
+	}
+
+	token, err := EncodeJWT(claim, signer, 0)
+	assert.NoError(t, err)
+
+	// JWTs inside JSON arrays are accepted as strings
+	claims, err := ReadClaims(strings.NewReader(`["` + token + `"]`))
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	assert.NoError(t, VerifyClaim(context.Background(), did.NewRegistry(), claims[0]))
+
+	// A JWT signed by another key cannot claim this issuer
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherSigner, err := jose.NewES256Signer(other)
+	assert.NoError(t, err)
+	forged, err := EncodeJWT(claim, otherSigner, 0)
+	assert.NoError(t, err)
+	decoded, err := DecodeJWT(forged)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyProof(decoded))
+	assert.Error(t, VerifyClaim(context.Background(), did.NewRegistry(), decoded))
+}
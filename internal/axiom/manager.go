@@ -1,16 +1,31 @@
 package axiom
 
 import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"axia/internal/crypto"
+	"axia/internal/delegation"
+	"axia/internal/did"
+	"axia/internal/jose"
 	"axia/internal/state"
 )
 
 // Manager handles creation and verification of axiomatic claims
 type Manager struct {
-	proofGen *crypto.ProofGenerator
-	state    *state.StateManager
-	logger   *logrus.Logger
+	proofGen      *crypto.ProofGenerator
+	state         *state.StateManager
+	signers       map[string]crypto.Signer
+	capabilities  map[string][]*delegation.Capability
+	keys          *delegation.History
+	chains        chains
+	defaultSigner crypto.Signer
+	custodians    *did.Custodians
+	logger        *logrus.Logger
 }
 
 // NewManager creates a new axiom manager
@@ -18,56 +33,242 @@ func NewManager(logger *logrus.Logger) *Manager {
 	return &Manager{
 		proofGen: crypto.NewProofGenerator(),
 		state:    state.NewNodeStateManager(logger),
-		logger:   logger,
+		signers:      make(map[string]crypto.Signer),
+		capabilities: make(map[string][]*delegation.Capability),
+		keys:         delegation.NewHistory(),
+		chains:       chains{heads: make(map[string]chainHead)},
+		custodians:   did.NewCustodians(did.NewRegistry()),
+		logger:       logger,
+	}
+}
+
+// RegisterSigner sets the key used to sign claims issued by agent. An
+// agent that is not a DID is bound to the key as its custodian.
+func (m *Manager) RegisterSigner(agent string, signer crypto.Signer) {
+	m.signers[agent] = signer
+	if !did.IsDID(agent) {
+		m.custodians.Register(agent, signer.PublicKey())
+	}
+}
+
+// RegisterCustodian allows publicKey to sign for name, an issuer that is
+// not a resolvable DID, or for every name with the prefix if name ends in
+// a colon, such as "twitter:"
+func (m *Manager) RegisterCustodian(name string, publicKey ed25519.PublicKey) {
+	m.custodians.Register(name, publicKey)
+}
+
+// RegisterCapabilities sets the capability chain attached to claims signed
+// for agent. The agent's registered signer must be the chain's final
+// delegate.
+func (m *Manager) RegisterCapabilities(agent string, chain []*delegation.Capability) {
+	m.capabilities[agent] = chain
+}
+
+// AddRotation records a key rotation. Claims made after it takes effect
+// must be made under the new key. An issuer's first rotation must start
+// from a key bound to the issuer, by custodian or DID resolution; later
+// ones continue from the key the previous one introduced.
+func (m *Manager) AddRotation(rotation *delegation.Rotation) error {
+	if _, ok := m.keys.KeyAt(rotation.Issuer, rotation.Effective); !ok {
+		previous, err := did.PublicKeyFromDIDKey(rotation.Previous)
+		if err != nil {
+			return fmt.Errorf("invalid previous key of rotation: %w", err)
+		}
+		if err := did.VerifyKey(context.Background(), m.custodians, rotation.Issuer, previous); err != nil {
+			return err
+		}
+	}
+	if err := m.keys.Add(rotation); err != nil {
+		return err
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"issuer":    rotation.Issuer,
+		"next":      rotation.Next,
+		"effective": rotation.Effective,
+	}).Info("Recorded key rotation")
+	return nil
+}
+
+// SetDefaultSigner sets the key used for agents without a registered
+// signer, e.g. custodial issuers such as Twitter reporters. It becomes the
+// custodian of agents that are neither DIDs nor bound to other keys.
+func (m *Manager) SetDefaultSigner(signer crypto.Signer) {
+	m.defaultSigner = signer
+	m.custodians.SetDefault(signer.PublicKey())
+}
+
+// SetResolver sets the resolver used to bind DID issuers to their keys
+func (m *Manager) SetResolver(resolver did.Resolver) {
+	m.custodians.SetResolver(resolver)
+}
+
+// Resolver returns the resolver that binds issuers to their keys: the
+// registered custodians, then DID resolution
+func (m *Manager) Resolver() did.Resolver {
+	return m.custodians
+}
+
+// SetCanonicalization selects the canonicalization used for new proofs
+func (m *Manager) SetCanonicalization(name string) error {
+	return m.proofGen.SetCanonicalization(name)
+}
+
+// VerifyClaim checks the claim's signature, that its issuer controls the
+// signing key and that the key had not been rotated away when the claim
+// was made
+func (m *Manager) VerifyClaim(ctx context.Context, claim *Claim) error {
+	if err := VerifyClaim(ctx, m.custodians, claim); err != nil {
+		return err
+	}
+	if _, ok := m.keys.KeyAt(claim.Issuer, claim.Issued); !ok {
+		return nil
 	}
+
+	rootKey, err := RootKey(claim)
+	if err != nil {
+		return err
+	}
+	return m.keys.CheckKey(claim.Issuer, rootKey, claim.Issued)
+}
+
+func (m *Manager) signerFor(agent string) (crypto.Signer, error) {
+	if signer, ok := m.signers[agent]; ok {
+		return signer, nil
+	}
+	if m.defaultSigner != nil {
+		return m.defaultSigner, nil
+	}
+	return nil, ErrNoSigningKey
 }
 
 // CreateClaim creates a new axiomatic claim
 func (m *Manager) CreateClaim(agent, subject, axiom string, confidence float64, tags []string) (*Claim, error) {
+	return m.createClaim(newBody(agent, subject, axiom, confidence, tags), nil, nil)
+}
+
+// CreateCoSignedClaim creates a claim that only takes effect once enough
+// of the policy's signers have signed it
+func (m *Manager) CreateCoSignedClaim(agent, subject, axiom string, confidence float64, tags []string, policy *ThresholdPolicy) (*Claim, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return m.createClaim(newBody(agent, subject, axiom, confidence, tags), policy, nil)
+}
+
+func newBody(agent, subject, axiom string, confidence float64, tags []string) Body {
+	return Body{
+		Context: "https://schema.axios.ai/",
+		Type:    "Axiom",
+		Subject: subject,
+		Agent:   agent,
+		Tags:    tags,
+		Rating: AxiomRating{
+			Context:         "https://schema.axios.ai/",
+			Type:            "Confidence",
+			MaxConfidence:   1.0,
+			MinConfidence:   0.0,
+			ConfidenceValue: confidence,
+			Axiom:           axiom,
+		},
+	}
+}
+
+// createClaim signs a claim with body as the next claim in its agent's
+// chain. With recipients, the body is encrypted to them.
+func (m *Manager) createClaim(body Body, policy *ThresholdPolicy, recipients []string) (*Claim, error) {
+	agent := body.Agent
 	m.logger.WithFields(logrus.Fields{
 		"agent":      agent,
-		"subject":    subject,
-		"confidence": confidence,
+		"subject":    body.Subject,
+		"confidence": body.Rating.ConfidenceValue,
 	}).Info("Creating new axiomatic claim")
 
+	signer, err := m.signerFor(agent)
+	if err != nil {
+		m.logger.WithField("agent", agent).Error("No signing key available for agent")
+		return nil, err
+	}
+
+	// Second precision survives every serialization the claim goes through
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// The chain stays locked until the claim extending it is signed
+	m.chains.mu.Lock()
+	defer m.chains.mu.Unlock()
+	head, err := m.chains.head(context.Background(), agent)
+	if err != nil {
+		return nil, err
+	}
+
 	claim := &Claim{
-		Context: "https://schema.axios.ai/AxiomaticClaim.jsonld",
-		Type:    "AxiomaticClaim",
-		Issuer:  agent,
-		Issued:  time.Now().UTC(),
-		ClaimBody: Body{
-			Context: "https://schema.axios.ai/",
-			Type:    "Axiom",
-			Subject: subject,
-			Agent:   agent,
-			Tags:    tags,
-			Rating: AxiomRating{
-				Context:         "https://schema.axios.ai/",
-				Type:           "Confidence",
-				MaxConfidence:  1.0,
-				MinConfidence:  0.0,
-				ConfidenceValue: confidence,
-				Axiom:          axiom,
+		ID:        "urn:uuid:" + uuid.New().String(),
+		Context:   "https://schema.axios.ai/AxiomaticClaim.jsonld",
+		Type:      "AxiomaticClaim",
+		Issuer:    agent,
+		Issued:    now,
+		Sequence:  head.sequence + 1,
+		Previous:  head.hash,
+		ClaimBody: body,
+		Proof: Proof{
+			Type:    ProofTypeAxiomatic,
+			Created: now,
+			Domain:  "axios.ai",
+			Verifier: Verifier{
+				ID: KeyID(signer.PublicKey()),
 			},
+			Canonicalization: m.proofGen.Canonicalization(),
+			CapabilityChain:  m.capabilities[agent],
+			Policy:           policy,
 		},
 	}
 
-	// Generate cryptographic proof
-	proof, err := m.proofGen.GenerateProof(claim)
+	// Private claims are checked against delegated scopes without their
+	// body, like verifiers will see them
+	if len(recipients) > 0 {
+		claim.Encrypted, err = EncryptBody(body, recipients)
+		if err != nil {
+			return nil, err
+		}
+		claim.ClaimBody = Body{}
+	}
+
+	// Refuse to sign claims the issuer's root key does not authorize: out
+	// of the delegated scope, rotated away, or not bound to the issuer
+	rootKey, err := RootKey(claim)
+	if err == nil {
+		err = m.keys.CheckKey(agent, rootKey, now)
+	}
+	if err == nil && agent != did.FromPublicKey(rootKey) {
+		err = did.VerifyKey(context.Background(), m.custodians, agent, rootKey)
+	}
+	if err != nil {
+		m.logger.WithError(err).WithField("agent", agent).Error("Signing key is not authorized for issuer")
+		return nil, fmt.Errorf("cannot issue claim as %s: %w", agent, err)
+	}
+
+	// Sign the claim, including its proof metadata, with the agent's key
+	proof, err := m.proofGen.SignProof(claim.signingPayload(), signer)
 	if err != nil {
 		m.logger.WithError(err).Error("Failed to generate proof for claim")
 		return nil, err
 	}
 
-	claim.Proof = Proof{
-		Type:       "AxiomaticVerification2024",
-		Created:    time.Now().UTC(),
-		Domain:     "axios.ai",
-		ProofValue: proof.Hash,
-		Verifier: Verifier{
-			ID: "Axiomatic-key:" + proof.Hash[:64],
-		},
+	claim.Proof.ProofValue = proof.Signature
+
+	if err := m.chains.advance(context.Background(), claim); err != nil {
+		return nil, err
 	}
 
 	return claim, nil
-} 
\ No newline at end of file
+}
+
+// EncodeJWT encodes the claim as an EdDSA JWT signed with its issuer's key
+func (m *Manager) EncodeJWT(claim *Claim, lifetime time.Duration) (string, error) {
+	signer, err := m.signerFor(claim.Issuer)
+	if err != nil {
+		return "", err
+	}
+	return EncodeJWT(claim, jose.NewEdDSASigner(signer), lifetime)
+}
@@ -0,0 +1,192 @@
+package axiom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// EncryptionAlgorithm names the encryption of private claim bodies: the
+// body is sealed with XSalsa20-Poly1305 under a random key, and the key is
+// sealed to each recipient's X25519 key with a NaCl sealed box
+const EncryptionAlgorithm = "X25519-XSalsa20-Poly1305"
+
+var (
+	ErrNoRecipients   = errors.New("private claim has no recipients")
+	ErrNotRecipient   = errors.New("key is not a recipient of the claim")
+	ErrDecryption     = errors.New("failed to decrypt claim body")
+	ErrPlaintextBody  = errors.New("private claim carries a plaintext body")
+	ErrEncryptedClaim = errors.New("claim body is encrypted")
+)
+
+// EncryptedBody is the claim body of a private claim, readable only by its
+// recipients. The issuer's signature covers the encrypted body, so anyone
+// can check the issuer, timestamp and proof of a private claim.
+type EncryptedBody struct {
+	Algorithm  string      `json:"algorithm"`
+	Recipients []Recipient `json:"recipients"`
+	Nonce      string      `json:"nonce"`
+	Ciphertext string      `json:"ciphertext"`
+}
+
+// Recipient holds the body key sealed to one recipient
+type Recipient struct {
+	// ID is the recipient's did:key
+	ID           string `json:"id"`
+	EncryptedKey string `json:"encryptedKey"`
+}
+
+// IsPrivate reports whether the claim body is encrypted
+func (c *Claim) IsPrivate() bool {
+	return c.Encrypted != nil
+}
+
+// IsRecipient reports whether the claim is private and addressed to id, a
+// did:key. It needs no private key, so callers can tell whether a key is
+// worth unlocking.
+func (c *Claim) IsRecipient(id string) bool {
+	if !c.IsPrivate() {
+		return false
+	}
+	key, err := did.KeyAgreementKey(id)
+	if err != nil {
+		return false
+	}
+	for _, recipient := range c.Encrypted.Recipients {
+		recipientKey, err := did.KeyAgreementKey(recipient.ID)
+		if err == nil && bytes.Equal(recipientKey, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncryptBody encrypts a claim body to the given did:key recipients
+func EncryptBody(body Body, recipients []string) (*EncryptedBody, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	plaintext, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claim body: %w", err)
+	}
+
+	var key [32]byte
+	var nonce [24]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	encrypted := &EncryptedBody{
+		Algorithm:  EncryptionAlgorithm,
+		Recipients: make([]Recipient, 0, len(recipients)),
+		Nonce:      base64.RawURLEncoding.EncodeToString(nonce[:]),
+		Ciphertext: base64.RawURLEncoding.EncodeToString(secretbox.Seal(nil, plaintext, &nonce, &key)),
+	}
+	for _, id := range recipients {
+		publicKey, err := did.KeyAgreementKey(id)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", id, err)
+		}
+
+		sealed, err := box.SealAnonymous(nil, key[:], (*[32]byte)(publicKey), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal key for %s: %w", id, err)
+		}
+		encrypted.Recipients = append(encrypted.Recipients, Recipient{
+			ID:           id,
+			EncryptedKey: base64.RawURLEncoding.EncodeToString(sealed),
+		})
+	}
+	return encrypted, nil
+}
+
+// Decrypt returns a copy of a private claim with its body decrypted by a
+// recipient's key. The copy keeps the encrypted body; its proof can only
+// be checked on the original claim.
+func Decrypt(claim *Claim, key *crypto.KeyPair) (*Claim, error) {
+	if !claim.IsPrivate() {
+		return claim, nil
+	}
+	encrypted := claim.Encrypted
+	if encrypted.Algorithm != EncryptionAlgorithm {
+		return nil, fmt.Errorf("%w: algorithm %s", ErrDecryption, encrypted.Algorithm)
+	}
+
+	privateKey := key.X25519PrivateKey()
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyKey []byte
+	for _, recipient := range encrypted.Recipients {
+		recipientKey, err := did.KeyAgreementKey(recipient.ID)
+		if err != nil || !bytes.Equal(recipientKey, publicKey) {
+			continue
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(recipient.EncryptedKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDecryption, err)
+		}
+
+		opened, ok := box.OpenAnonymous(nil, sealed, (*[32]byte)(publicKey), (*[32]byte)(privateKey))
+		if !ok || len(opened) != 32 {
+			return nil, ErrDecryption
+		}
+		bodyKey = opened
+		break
+	}
+	if bodyKey == nil {
+		return nil, ErrNotRecipient
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(encrypted.Nonce)
+	if err != nil || len(nonce) != 24 {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrDecryption)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(encrypted.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryption, err)
+	}
+
+	plaintext, ok := secretbox.Open(nil, ciphertext, (*[24]byte)(nonce), (*[32]byte)(bodyKey))
+	if !ok {
+		return nil, ErrDecryption
+	}
+
+	opened := *claim
+	if err := json.Unmarshal(plaintext, &opened.ClaimBody); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryption, err)
+	}
+	return &opened, nil
+}
+
+// hasPlaintextBody reports whether a private claim also carries a body,
+// which its signature does not cover
+func hasPlaintextBody(claim *Claim) bool {
+	return claim.IsPrivate() && !reflect.ValueOf(claim.ClaimBody).IsZero()
+}
+
+// CreatePrivateClaim creates a claim whose body only the recipients, given
+// as did:key identifiers, can read
+func (m *Manager) CreatePrivateClaim(agent, subject, axiom string, confidence float64, tags []string, recipients []string) (*Claim, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return m.createClaim(newBody(agent, subject, axiom, confidence, tags), nil, recipients)
+}
@@ -0,0 +1,222 @@
+package axiom
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"axia/internal/crypto"
+	"axia/internal/delegation"
+	"axia/internal/did"
+)
+
+const (
+	// ProofTypeAxiomatic is the proof type produced by Manager.CreateClaim
+	ProofTypeAxiomatic = "AxiomaticVerification2024"
+
+	// VerifierKeyPrefix prefixes the hex-encoded Ed25519 key in Verifier.ID
+	VerifierKeyPrefix = "Axiomatic-key:"
+)
+
+var (
+	ErrUnsignedClaim     = errors.New("claim has no proof value")
+	ErrUnsupportedProof  = errors.New("unsupported proof type")
+	ErrInvalidVerifier   = errors.New("invalid verifier key")
+	ErrNoSigningKey      = errors.New("no signing key registered for agent")
+	ErrProofVerification = errors.New("proof verification failed")
+)
+
+// KeyID returns the verifier identifier for an Ed25519 public key
+func KeyID(publicKey ed25519.PublicKey) string {
+	return VerifierKeyPrefix + hex.EncodeToString(publicKey)
+}
+
+// ParseKeyID extracts the Ed25519 public key from a verifier identifier.
+// Both Axiomatic-key identifiers and did:key verification method URLs
+// (did:key:z6Mk...#z6Mk...) are accepted.
+func ParseKeyID(id string) (ed25519.PublicKey, error) {
+	if strings.HasPrefix(id, "did:key:") {
+		didKey, _, _ := strings.Cut(id, "#")
+		key, err := did.PublicKeyFromDIDKey(didKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidVerifier, err)
+		}
+		return key, nil
+	}
+
+	if !strings.HasPrefix(id, VerifierKeyPrefix) {
+		return nil, ErrInvalidVerifier
+	}
+
+	key, err := hex.DecodeString(strings.TrimPrefix(id, VerifierKeyPrefix))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidVerifier
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// ProofVerifier checks the signature of a claim carrying a proof type
+// other than AxiomaticVerification2024
+type ProofVerifier func(claim *Claim) error
+
+var proofVerifiers = make(map[string]ProofVerifier)
+
+// RegisterProofVerifier makes VerifyProof accept claims whose proof has
+// the given type. Packages implementing additional proof formats register
+// themselves from init.
+func RegisterProofVerifier(proofType string, verifier ProofVerifier) {
+	proofVerifiers[proofType] = verifier
+}
+
+// UnsignedCopy returns a copy of the claim without the co-signatures,
+// disclosures and timestamp token added after its issuer signed it. The
+// issuer's proof value is kept.
+func UnsignedCopy(claim *Claim) *Claim {
+	unsigned := *claim
+	unsigned.Proof.CoSignatures = nil
+	unsigned.Proof.Disclosures = nil
+	unsigned.Proof.Timestamp = nil
+	return &unsigned
+}
+
+// signingPayload returns the view of the claim covered by its signature:
+// the full claim, including proof metadata, as UnsignedCopy returns it
+// with the proof value removed
+func (c *Claim) signingPayload() *Claim {
+	payload := UnsignedCopy(c)
+	payload.Proof.ProofValue = ""
+	return payload
+}
+
+// SameClaim reports whether a and b are the same signed claim, which may
+// differ only in co-signatures, disclosures and timestamp token
+func SameClaim(a, b *Claim) bool {
+	if a.Proof.ProofValue != b.Proof.ProofValue {
+		return false
+	}
+	payloadA, errA := json.Marshal(a.signingPayload())
+	payloadB, errB := json.Marshal(b.signingPayload())
+	return errA == nil && errB == nil && bytes.Equal(payloadA, payloadB)
+}
+
+// VerifyProof checks that the claim carries a valid signature by the key
+// named in its proof verifier. It returns an error for unsigned, tampered
+// or malformed claims.
+func VerifyProof(claim *Claim) error {
+	if claim.Proof.ProofValue == "" {
+		return ErrUnsignedClaim
+	}
+	// Signatures cover the encrypted body only
+	if hasPlaintextBody(claim) {
+		return ErrPlaintextBody
+	}
+
+	if claim.Proof.Type != ProofTypeAxiomatic {
+		verifier, ok := proofVerifiers[claim.Proof.Type]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupportedProof, claim.Proof.Type)
+		}
+		if err := verifier(claim); err != nil {
+			return fmt.Errorf("%w: %v", ErrProofVerification, err)
+		}
+		if err := verifyDisclosures(claim); err != nil {
+			return err
+		}
+		if err := verifyTimestamp(claim); err != nil {
+			return err
+		}
+		return verifyCoSignatures(claim)
+	}
+
+	publicKey, err := ParseKeyID(claim.Proof.Verifier.ID)
+	if err != nil {
+		return err
+	}
+
+	proofGen := crypto.NewProofGenerator()
+	if err := proofGen.SetCanonicalization(claim.Proof.Canonicalization); err != nil {
+		return err
+	}
+
+	err = proofGen.VerifySignature(claim.signingPayload(), claim.Proof.ProofValue, publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProofVerification, err)
+	}
+	if err := verifyDisclosures(claim); err != nil {
+		return err
+	}
+	if err := verifyTimestamp(claim); err != nil {
+		return err
+	}
+
+	return verifyCoSignatures(claim)
+}
+
+// RootKey returns the issuer key a claim is made under: the key that signed
+// it or, for delegated claims, the key at the root of its capability chain.
+// The chain is verified against the claim on the way.
+func RootKey(claim *Claim) (ed25519.PublicKey, error) {
+	publicKey, err := ParseKeyID(claim.Proof.Verifier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(claim.Proof.CapabilityChain) == 0 {
+		return publicKey, nil
+	}
+
+	return delegation.VerifyChain(claim.Proof.CapabilityChain, claim.Issuer, publicKey, delegation.Use{
+		Subject:    claim.ClaimBody.Subject,
+		Tags:       claim.ClaimBody.Tags,
+		Confidence: claim.ClaimBody.Rating.ConfidenceValue,
+		Time:       claim.Issued,
+	})
+}
+
+// VerifyIssuer checks that the issuer resolves to the key that signed the
+// claim, or to the root key of its capability chain. Issuers that are not
+// DIDs, such as custodial twitter: issuers, must resolve to the key of
+// their custodian, see did.Custodians.
+func VerifyIssuer(ctx context.Context, resolver did.Resolver, claim *Claim) error {
+	if len(claim.Proof.CapabilityChain) > 0 {
+		rootKey, err := RootKey(claim)
+		if err != nil {
+			return err
+		}
+		if claim.Issuer == did.FromPublicKey(rootKey) {
+			return nil
+		}
+		return did.VerifyKey(ctx, resolver, claim.Issuer, rootKey)
+	}
+
+	// A did:key is bound to exactly the key it encodes, whatever its type
+	verifierDID, _, _ := strings.Cut(claim.Proof.Verifier.ID, "#")
+	if verifierDID == claim.Issuer && strings.HasPrefix(verifierDID, "did:key:") {
+		return nil
+	}
+
+	publicKey, err := ParseKeyID(claim.Proof.Verifier.ID)
+	if err != nil {
+		return err
+	}
+
+	return did.VerifyKey(ctx, resolver, claim.Issuer, publicKey)
+}
+
+// VerifyClaim runs all checks needed to accept a claim from an untrusted
+// source: the proof and co-signatures, and the binding of the issuer and
+// co-signers to their keys
+func VerifyClaim(ctx context.Context, resolver did.Resolver, claim *Claim) error {
+	if err := VerifyProof(claim); err != nil {
+		return err
+	}
+	if err := VerifyIssuer(ctx, resolver, claim); err != nil {
+		return err
+	}
+	return verifyCoSigners(ctx, resolver, claim)
+}
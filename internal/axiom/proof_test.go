@@ -0,0 +1,435 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/delegation"
+	"axia/internal/did"
+	"axia/internal/timestamp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager(t *testing.T) (*Manager, *crypto.KeyPair) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	manager := NewManager(logger)
+	manager.RegisterSigner("agent:alice", key)
+	return manager, key
+}
+
+func TestVerifyProof(t *testing.T) {
+	manager, key := newTestManager(t)
+
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, []string{"physics"})
+	assert.NoError(t, err)
+	assert.Equal(t, KeyID(key.PublicKey()), claim.Proof.Verifier.ID)
+	assert.NoError(t, VerifyProof(claim))
+
+	// Tampering with any signed field invalidates the proof
+	tampered := *claim
+	tampered.ClaimBody.Rating.ConfidenceValue = 0.1
+	assert.ErrorIs(t, VerifyProof(&tampered), ErrProofVerification)
+
+	// Swapping in another verifier key invalidates the proof
+	other, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	forged := *claim
+	forged.Proof.Verifier.ID = KeyID(other.PublicKey())
+	assert.ErrorIs(t, VerifyProof(&forged), ErrProofVerification)
+
+	unsigned := *claim
+	unsigned.Proof.ProofValue = ""
+	assert.ErrorIs(t, VerifyProof(&unsigned), ErrUnsignedClaim)
+}
+
+func TestCreateClaimRequiresSigner(t *testing.T) {
+	manager, _ := newTestManager(t)
+
+	_, err := manager.CreateClaim("agent:mallory", "did:fact:sky", "Sky is green", 0.9, nil)
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestCreateClaimBindsDIDIssuer(t *testing.T) {
+	manager, key := newTestManager(t)
+	issuer := did.FromPublicKey(key.PublicKey())
+	manager.RegisterSigner(issuer, key)
+
+	claim, err := manager.CreateClaim(issuer, "did:fact:sky", "Sky is blue", 0.99, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+
+	// A DID whose document does not list the signing key is refused
+	other, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	impostor := did.FromPublicKey(other.PublicKey())
+	manager.RegisterSigner(impostor, key)
+
+	_, err = manager.CreateClaim(impostor, "did:fact:sky", "Sky is green", 0.99, nil)
+	assert.ErrorIs(t, err, did.ErrKeyNotAuthorized)
+}
+
+func TestProofSurvivesReserialization(t *testing.T) {
+	for _, canonicalization := range []string{crypto.CanonicalizationJCS, crypto.CanonicalizationRDF} {
+		manager, _ := newTestManager(t)
+		assert.NoError(t, manager.SetCanonicalization(canonicalization))
+
+		claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, []string{"physics", "optics"})
+		assert.NoError(t, err)
+
+		data, err := json.MarshalIndent(claim, "", "    ")
+		assert.NoError(t, err)
+
+		// Confidence serialized as a string, as in JSON-LD tooling output
+		reformatted := strings.Replace(string(data), `"confidenceValue": 0.99`, `"confidenceValue": "0.99"`, 1)
+		assert.NotEqual(t, string(data), reformatted)
+
+		var decoded Claim
+		assert.NoError(t, json.Unmarshal([]byte(reformatted), &decoded))
+		assert.NoError(t, VerifyProof(&decoded), canonicalization)
+	}
+}
+
+func TestRevokeClaim(t *testing.T) {
+	manager, _ := newTestManager(t)
+
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, nil)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(claim.ID, "urn:uuid:"))
+
+	revocation, err := manager.RevokeClaim(claim, "superseded")
+	assert.NoError(t, err)
+	assert.Equal(t, claim.ID, revocation.ClaimID)
+	assert.NoError(t, manager.VerifyRevocation(context.Background(), revocation, claim))
+
+	// Another agent's key cannot revoke the claim
+	other, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	manager.RegisterSigner("agent:alice", other)
+	forged, err := manager.RevokeClaim(claim, "")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, manager.VerifyRevocation(context.Background(), forged, claim), ErrRevocationKey)
+
+	revocation.Reason = "tampered"
+	assert.ErrorIs(t, VerifyRevocationProof(revocation), ErrProofVerification)
+}
+
+func TestDelegatedClaim(t *testing.T) {
+	manager, root := newTestManager(t)
+	worker, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	capability, err := delegation.Delegate("agent:alice", root, worker.PublicKey(), delegation.Scope{
+		Subjects: []string{"did:fact:sky"},
+	}, now.Add(-time.Minute), now.Add(time.Hour), nil)
+	assert.NoError(t, err)
+
+	manager.RegisterSigner("agent:alice", worker)
+	manager.RegisterCapabilities("agent:alice", []*delegation.Capability{capability})
+
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+
+	_, err = manager.CreateClaim("agent:alice", "did:fact:grass", "Grass is green", 0.9, nil)
+	assert.ErrorIs(t, err, delegation.ErrOutOfScope)
+
+	// The delegate cannot move the claim out of scope after signing
+	claim.ClaimBody.Subject = "did:fact:grass"
+	assert.Error(t, manager.VerifyClaim(context.Background(), claim))
+
+	// Only a key bound to the issuer can start its key history
+	next, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	foreign, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	hijack, err := delegation.Rotate("agent:alice", foreign, next.PublicKey(), now.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, manager.AddRotation(hijack), did.ErrKeyNotAuthorized)
+
+	// Rotating the root key retires its delegations for new claims
+	rotation, err := delegation.Rotate("agent:alice", root, next.PublicKey(), now.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.NoError(t, manager.AddRotation(rotation))
+
+	_, err = manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil)
+	assert.ErrorIs(t, err, delegation.ErrRotatedKey)
+}
+
+func TestCoSignedClaim(t *testing.T) {
+	manager, _ := newTestManager(t)
+	for _, agent := range []string{"agent:bob", "agent:dave"} {
+		key, err := crypto.GenerateKeyPair()
+		assert.NoError(t, err)
+		manager.RegisterSigner(agent, key)
+	}
+
+	policy := &ThresholdPolicy{Threshold: 2, Signers: []string{"agent:alice", "agent:bob", "agent:carol"}}
+	claim, err := manager.CreateCoSignedClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil, policy)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+	assert.False(t, ThresholdMet(claim))
+
+	assert.ErrorIs(t, manager.CoSign(claim, "agent:dave"), ErrNotPolicySigner)
+	assert.NoError(t, manager.CoSign(claim, "agent:bob"))
+	assert.ErrorIs(t, manager.CoSign(claim, "agent:bob"), ErrDuplicateSigner)
+
+	assert.True(t, ThresholdMet(claim))
+	assert.Equal(t, []string{"agent:alice", "agent:bob"}, claim.Signers())
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+
+	// Co-signatures leave the claim the same signed claim
+	unsigned := *claim
+	unsigned.Proof.CoSignatures = nil
+	assert.True(t, SameClaim(&unsigned, claim))
+	unsigned.ID = "urn:uuid:other"
+	assert.False(t, SameClaim(&unsigned, claim))
+
+	// The issuer's signature does not cover co-signatures, but each
+	// co-signature covers the claim
+	claim.Proof.CoSignatures[0].Signer = "agent:carol"
+	assert.ErrorIs(t, VerifyProof(claim), ErrCoSignatureBroken)
+
+	// The policy is covered by the issuer's signature
+	claim.Proof.CoSignatures[0].Signer = "agent:bob"
+	claim.Proof.Policy.Threshold = 1
+	assert.ErrorIs(t, VerifyProof(claim), ErrProofVerification)
+
+	_, err = manager.EncodeJWT(claim, time.Hour)
+	assert.ErrorIs(t, err, ErrJWTProofData)
+}
+
+func TestVerifyClaimBindsCustodians(t *testing.T) {
+	manager, _ := newTestManager(t)
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+
+	// Another node's key cannot sign for the agent or co-sign as another
+	mallory, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	other := NewManager(manager.logger)
+	other.RegisterSigner("agent:alice", mallory)
+	other.RegisterSigner("agent:carol", mallory)
+	forged, err := other.CreateClaim("agent:alice", "did:fact:sky", "Sky is green", 0.9, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyProof(forged))
+	assert.ErrorIs(t, manager.VerifyClaim(context.Background(), forged), did.ErrKeyNotAuthorized)
+
+	assert.NoError(t, other.CoSign(claim, "agent:carol"))
+	assert.NoError(t, VerifyProof(claim))
+	assert.ErrorIs(t, manager.VerifyClaim(context.Background(), claim), ErrCoSignatureBroken)
+
+	// Names without a custodian are bound to no key at all
+	assert.ErrorIs(t, VerifyClaim(context.Background(), did.NewCustodians(nil), forged), did.ErrNoCustodian)
+
+	// A custodian registered for a prefix signs for every name under it
+	node, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	manager.SetDefaultSigner(node)
+	tweet, err := manager.CreateClaim("twitter:42", "did:fact:sky", "Sky is blue", 0.7, nil)
+	assert.NoError(t, err)
+	custodians := did.NewCustodians(nil)
+	custodians.Register("twitter:", node.PublicKey())
+	assert.NoError(t, VerifyClaim(context.Background(), custodians, tweet))
+	assert.Error(t, VerifyClaim(context.Background(), other.Resolver(), tweet))
+}
+
+func TestClaimChain(t *testing.T) {
+	manager, key := newTestManager(t)
+	first, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil)
+	assert.NoError(t, err)
+	second, err := manager.CreateClaim("agent:alice", "did:fact:sea", "Sea is blue", 0.8, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(1), first.Sequence)
+	assert.Empty(t, first.Previous)
+	assert.Equal(t, uint64(2), second.Sequence)
+	follows, err := Follows(second, first)
+	assert.NoError(t, err)
+	assert.True(t, follows)
+	assert.Empty(t, CheckChain(second, []*Claim{first, second}))
+
+	// A second node with the same key starts another history
+	other := NewManager(manager.logger)
+	other.RegisterSigner("agent:alice", key)
+	forked, err := other.CreateClaim("agent:alice", "did:fact:sky", "Sky is green", 0.9, nil)
+	assert.NoError(t, err)
+	forkedNext, err := other.CreateClaim("agent:alice", "did:fact:sea", "Sea is green", 0.8, nil)
+	assert.NoError(t, err)
+
+	found := CheckChain(forked, []*Claim{first, second})
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, EquivocationDuplicate, found[0].Kind)
+		assert.Equal(t, EquivocationFork, found[1].Kind)
+		assert.Equal(t, []*Claim{forked, second}, found[1].Claims)
+	}
+	found = CheckChain(forkedNext, []*Claim{first})
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, EquivocationFork, found[0].Kind)
+		assert.NoError(t, found[0].Verify(context.Background(), manager.Resolver()))
+	}
+
+	gaps := ChainGaps([]*Claim{second, forkedNext})
+	if assert.Len(t, gaps, 1) {
+		assert.Equal(t, uint64(1), gaps[0].Sequence)
+	}
+	assert.False(t, gaps[0].Conclusive())
+
+	// Anyone can publish the evidence as a claim against the issuer
+	reporter, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	manager.RegisterSigner("agent:bob", reporter)
+	report, err := manager.ReportEquivocation(context.Background(), "agent:bob", found[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "agent:alice", report.ClaimBody.Subject)
+	assert.Equal(t, 1.0, report.ClaimBody.Rating.ConfidenceValue)
+	assert.NoError(t, VerifyProof(report))
+	assert.NoError(t, VerifyEquivocationClaim(context.Background(), manager.Resolver(), report))
+
+	_, err = manager.ReportEquivocation(context.Background(), "agent:bob", gaps[0])
+	assert.ErrorIs(t, err, ErrNotEquivocation)
+
+	// Claims under another key bound to the issuer do not frame it
+	custodian, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	third := NewManager(manager.logger)
+	third.RegisterSigner("agent:alice", custodian)
+	framed, err := third.CreateClaim("agent:alice", "did:fact:sky", "Sky is red", 0.9, nil)
+	assert.NoError(t, err)
+	manager.RegisterCustodian("agent:alice", custodian.PublicKey())
+	found = CheckChain(framed, []*Claim{first})
+	if assert.Len(t, found, 1) {
+		assert.ErrorIs(t, manager.VerifyEquivocation(context.Background(), found[0]), ErrNotEquivocation)
+	}
+}
+
+func TestPrivateClaim(t *testing.T) {
+	manager, _ := newTestManager(t)
+	analyst, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	outsider, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	claim, err := manager.CreatePrivateClaim("agent:alice", "did:fact:token", "Token is a rug pull", 0.9,
+		[]string{"rug"}, []string{did.FromPublicKey(analyst.PublicKey())})
+	assert.NoError(t, err)
+	assert.True(t, claim.IsPrivate())
+	assert.Empty(t, claim.ClaimBody.Subject)
+
+	// Anyone can check the proof and who it is addressed to, only
+	// recipients can read the body
+	assert.NoError(t, manager.VerifyClaim(context.Background(), claim))
+	assert.True(t, claim.IsRecipient(did.FromPublicKey(analyst.PublicKey())))
+	assert.False(t, claim.IsRecipient(did.FromPublicKey(outsider.PublicKey())))
+	_, err = Decrypt(claim, outsider)
+	assert.ErrorIs(t, err, ErrNotRecipient)
+
+	opened, err := Decrypt(claim, analyst)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:fact:token", opened.ClaimBody.Subject)
+	assert.Equal(t, "Token is a rug pull", opened.ClaimBody.Rating.Axiom)
+
+	// A body next to the encrypted one is not covered by the signature
+	assert.ErrorIs(t, VerifyProof(opened), ErrPlaintextBody)
+
+	// The encrypted body is
+	data, err := json.Marshal(claim)
+	assert.NoError(t, err)
+	tampered := &Claim{}
+	assert.NoError(t, json.Unmarshal(data, tampered))
+	tampered.Encrypted.Recipients = tampered.Encrypted.Recipients[:0]
+	assert.ErrorIs(t, VerifyProof(tampered), ErrProofVerification)
+}
+
+func TestSelectiveDisclosure(t *testing.T) {
+	manager, _ := newTestManager(t)
+
+	claim, err := manager.CreateSelectiveClaim("agent:alice", "did:fact:token", "Token is a rug pull", 0.9,
+		[]string{"rug"}, []string{DisclosableAxiom, DisclosableConfidence})
+	assert.NoError(t, err)
+	assert.Empty(t, claim.ClaimBody.Rating.Axiom)
+	assert.Len(t, claim.ClaimBody.SD, 2)
+	assert.NoError(t, VerifyProof(claim))
+
+	// Any subset of the disclosures still verifies
+	presented, err := Present(claim, []string{DisclosableConfidence})
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyProof(presented))
+
+	disclosed, err := Disclose(presented)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.9, disclosed.ClaimBody.Rating.ConfidenceValue)
+	assert.Empty(t, disclosed.ClaimBody.Rating.Axiom)
+
+	// Disclosures survive the JWT form
+	token, err := manager.EncodeJWT(presented, 0)
+	assert.NoError(t, err)
+	decoded, err := DecodeJWT(token)
+	assert.NoError(t, err)
+	assert.Equal(t, presented.Proof.Disclosures, decoded.Proof.Disclosures)
+	assert.NoError(t, VerifyProof(decoded))
+
+	// A disclosure the issuer did not list is rejected
+	forged := &Disclosure{Salt: "salt", Name: DisclosableAxiom, Value: json.RawMessage(`"Token is safe"`)}
+	encoded, err := forged.Encode()
+	assert.NoError(t, err)
+	presented.Proof.Disclosures = append(presented.Proof.Disclosures, encoded)
+	assert.ErrorIs(t, VerifyProof(presented), ErrInvalidDisclosure)
+}
+
+func TestTimestampedClaim(t *testing.T) {
+	manager, key := newTestManager(t)
+	authorityKey, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	authority, err := timestamp.NewAuthority(context.Background(), timestamp.NewMemoryStorage(), authorityKey, logger)
+	assert.NoError(t, err)
+
+	claim, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, []string{"physics"})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, RequireTimestamp(claim, []string{authority.ID()}), ErrNoTimestamp)
+
+	assert.NoError(t, Timestamp(context.Background(), claim, authority))
+	assert.NoError(t, VerifyProof(claim))
+	assert.NoError(t, RequireTimestamp(claim, []string{authority.ID()}))
+	assert.ErrorIs(t, RequireTimestamp(claim, []string{"did:key:z6MkOther"}), ErrUntrustedTimestamp)
+
+	// Tokens from other authorities are replaced
+	otherKey, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	otherAuthority, err := timestamp.NewAuthority(context.Background(), timestamp.NewMemoryStorage(), otherKey, logger)
+	assert.NoError(t, err)
+	assert.NoError(t, Timestamp(context.Background(), claim, otherAuthority, authority.ID()))
+	assert.Equal(t, authority.ID(), claim.Proof.Timestamp.Authority)
+	assert.NoError(t, Timestamp(context.Background(), claim, otherAuthority))
+	assert.Equal(t, otherAuthority.ID(), claim.Proof.Timestamp.Authority)
+
+	// The token covers this claim only
+	other, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is green", 0.1, nil)
+	assert.NoError(t, err)
+	other.Proof.Timestamp = claim.Proof.Timestamp
+	assert.ErrorIs(t, VerifyProof(other), timestamp.ErrImprintMismatch)
+
+	// A claim dated after its timestamp was misdated by its issuer
+	late, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.99, nil)
+	assert.NoError(t, err)
+	late.Issued = late.Issued.Add(time.Hour)
+	proof, err := manager.proofGen.SignProof(late.signingPayload(), key)
+	assert.NoError(t, err)
+	late.Proof.ProofValue = proof.Signature
+	assert.NoError(t, Timestamp(context.Background(), late, authority))
+	assert.ErrorIs(t, VerifyProof(late), ErrIssuedAfterTimestamp)
+}
@@ -0,0 +1,163 @@
+package axiom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+)
+
+// RevocationType is the type of revocation records
+const RevocationType = "AxiomaticRevocation"
+
+var (
+	ErrRevocationClaim = errors.New("revocation does not reference the claim")
+	ErrRevocationKey   = errors.New("revocation is not signed by the claim's issuer")
+)
+
+// Revocation withdraws a claim. It is signed by the claim's issuer, either
+// with the key that signed the claim or with another key bound to the
+// issuer's DID, so that claims signed with a compromised key can be revoked.
+type Revocation struct {
+	Context string    `json:"@context"`
+	Type    string    `json:"type"`
+	ClaimID string    `json:"claimId"`
+	Issuer  string    `json:"issuer"`
+	Reason  string    `json:"reason,omitempty"`
+	Revoked time.Time `json:"revoked"`
+	Proof   Proof     `json:"proof"`
+}
+
+// ClaimID returns the identifier revocations use for a claim: its ID, or
+// for claims created without one, a URN derived from the claim signature
+func ClaimID(claim *Claim) string {
+	if claim.ID != "" {
+		return claim.ID
+	}
+	sum := sha256.Sum256([]byte(claim.Proof.ProofValue))
+	return "urn:axia:proof:" + hex.EncodeToString(sum[:])
+}
+
+// signingPayload returns the revocation with the proof value removed
+func (r *Revocation) signingPayload() *Revocation {
+	payload := *r
+	payload.Proof.ProofValue = ""
+	return &payload
+}
+
+// RevokeClaim creates a revocation of claim signed with its issuer's key
+func (m *Manager) RevokeClaim(claim *Claim, reason string) (*Revocation, error) {
+	signer, err := m.signerFor(claim.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	revocation := &Revocation{
+		Context: "https://schema.axios.ai/AxiomaticClaim.jsonld",
+		Type:    RevocationType,
+		ClaimID: ClaimID(claim),
+		Issuer:  claim.Issuer,
+		Reason:  reason,
+		Revoked: now,
+		Proof: Proof{
+			Type:    ProofTypeAxiomatic,
+			Created: now,
+			Domain:  "axios.ai",
+			Verifier: Verifier{
+				ID: KeyID(signer.PublicKey()),
+			},
+			Canonicalization: m.proofGen.Canonicalization(),
+		},
+	}
+
+	proof, err := m.proofGen.SignProof(revocation.signingPayload(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign revocation: %w", err)
+	}
+	revocation.Proof.ProofValue = proof.Signature
+
+	m.logger.WithField("claim_id", revocation.ClaimID).Info("Revoked claim")
+	return revocation, nil
+}
+
+// VerifyRevocation checks the revocation of claim against the resolver.
+// Keys that replaced the claim's root key by rotation may revoke it too.
+func (m *Manager) VerifyRevocation(ctx context.Context, revocation *Revocation, claim *Claim) error {
+	err := VerifyRevocation(ctx, m.custodians, revocation, claim)
+	if !errors.Is(err, ErrRevocationKey) {
+		return err
+	}
+
+	revocationKey, keyErr := ParseKeyID(revocation.Proof.Verifier.ID)
+	if keyErr != nil {
+		return err
+	}
+	if current, ok := m.keys.KeyAt(claim.Issuer, revocation.Revoked); ok && current == did.FromPublicKey(revocationKey) {
+		return nil
+	}
+	return err
+}
+
+// VerifyRevocationProof checks the revocation's signature only
+func VerifyRevocationProof(revocation *Revocation) error {
+	if revocation.Proof.ProofValue == "" {
+		return ErrUnsignedClaim
+	}
+	if revocation.Type != RevocationType || revocation.Proof.Type != ProofTypeAxiomatic {
+		return fmt.Errorf("%w: %s", ErrUnsupportedProof, revocation.Proof.Type)
+	}
+
+	publicKey, err := ParseKeyID(revocation.Proof.Verifier.ID)
+	if err != nil {
+		return err
+	}
+
+	proofGen := crypto.NewProofGenerator()
+	if err := proofGen.SetCanonicalization(revocation.Proof.Canonicalization); err != nil {
+		return err
+	}
+
+	err = proofGen.VerifySignature(revocation.signingPayload(), revocation.Proof.ProofValue, publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProofVerification, err)
+	}
+	return nil
+}
+
+// VerifyRevocation checks that revocation is a valid revocation of claim:
+// it is signed, names the claim and its issuer, and the signing key is the
+// claim's key or, for DID issuers, a key the DID currently lists
+func VerifyRevocation(ctx context.Context, resolver did.Resolver, revocation *Revocation, claim *Claim) error {
+	if err := VerifyRevocationProof(revocation); err != nil {
+		return err
+	}
+	if revocation.ClaimID != ClaimID(claim) || revocation.Issuer != claim.Issuer {
+		return ErrRevocationClaim
+	}
+
+	revocationKey, err := ParseKeyID(revocation.Proof.Verifier.ID)
+	if err != nil {
+		return err
+	}
+	if claimKey, err := ParseKeyID(claim.Proof.Verifier.ID); err == nil && claimKey.Equal(revocationKey) {
+		return nil
+	}
+	// The root key may revoke claims signed by its delegates
+	if rootKey, err := RootKey(claim); err == nil && rootKey.Equal(revocationKey) {
+		return nil
+	}
+
+	if did.IsDID(claim.Issuer) && resolver != nil {
+		if err := did.VerifyKey(ctx, resolver, claim.Issuer, revocationKey); err != nil {
+			return fmt.Errorf("%w: %v", ErrRevocationKey, err)
+		}
+		return nil
+	}
+	return ErrRevocationKey
+}
@@ -0,0 +1,102 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/timestamp"
+)
+
+// MaxClockSkew is how far a claim's own dates may run ahead of its
+// timestamp before the claim counts as misdated
+const MaxClockSkew = 5 * time.Minute
+
+var (
+	ErrIssuedAfterTimestamp = errors.New("claim is dated after its timestamp")
+	ErrNoTimestamp          = errors.New("claim has no timestamp")
+	ErrUntrustedTimestamp   = errors.New("claim is timestamped by an untrusted authority")
+)
+
+// Stamper issues timestamp tokens for message imprints, like
+// timestamp.Authority
+type Stamper interface {
+	// ID returns the did:key of the authority
+	ID() string
+	Stamp(ctx context.Context, imprint string) (*timestamp.Token, error)
+}
+
+// timestampData returns the data a claim's timestamp covers: the issuer's
+// signature, which commits to the signed claim. Co-signatures and
+// disclosures come and go after issuance and are not covered.
+func timestampData(claim *Claim) []byte {
+	return []byte(claim.Proof.ProofValue)
+}
+
+// Timestamp adds a token from stamper to the proof of a signed claim,
+// giving it an upper bound on its creation time. Claims keep a token they
+// already carry only if it is from stamper or one of the trusted
+// authorities; anyone can run an authority that backdates its tokens.
+func Timestamp(ctx context.Context, claim *Claim, stamper Stamper, trusted ...string) error {
+	if claim.Proof.ProofValue == "" {
+		return ErrUnsignedClaim
+	}
+	if RequireTimestamp(claim, append([]string{stamper.ID()}, trusted...)) == nil {
+		return nil
+	}
+
+	imprint, err := timestamp.Imprint(timestampData(claim))
+	if err != nil {
+		return err
+	}
+	token, err := stamper.Stamp(ctx, imprint)
+	if err != nil {
+		return fmt.Errorf("failed to timestamp claim: %w", err)
+	}
+	claim.Proof.Timestamp = token
+	return nil
+}
+
+// verifyTimestamp checks the claim's timestamp token, if any: it must be
+// signed by its authority, cover the claim, and not predate the claim's
+// own dates
+func verifyTimestamp(claim *Claim) error {
+	token := claim.Proof.Timestamp
+	if token == nil {
+		return nil
+	}
+	if err := token.Verify(); err != nil {
+		return err
+	}
+
+	covers, err := token.Covers(timestampData(claim))
+	if err != nil {
+		return err
+	}
+	if !covers {
+		return timestamp.ErrImprintMismatch
+	}
+
+	latest := token.Time.Add(MaxClockSkew)
+	if claim.Issued.After(latest) || claim.Proof.Created.After(latest) {
+		return fmt.Errorf("%w: issued %s, timestamped %s", ErrIssuedAfterTimestamp,
+			claim.Issued.Format(time.RFC3339), token.Time.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// RequireTimestamp checks that the claim is timestamped by one of the
+// given authorities. The token itself is checked by VerifyProof.
+func RequireTimestamp(claim *Claim, authorities []string) error {
+	token := claim.Proof.Timestamp
+	if token == nil {
+		return ErrNoTimestamp
+	}
+	for _, authority := range authorities {
+		if token.Authority == authority {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUntrustedTimestamp, token.Authority)
+}
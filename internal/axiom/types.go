@@ -1,17 +1,35 @@
 package axiom
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 	"github.com/google/uuid"
+	"axia/internal/delegation"
+	"axia/internal/timestamp"
 )
 
 // Claim represents an axiomatic claim in the trust network
 type Claim struct {
+	// ID is an optional URI identifying the claim, e.g. urn:uuid:...
+	ID         string    `json:"id,omitempty"`
 	Context    string    `json:"@context"`
 	Type       string    `json:"type"`
 	Issuer     string    `json:"issuer"`
 	Issued     time.Time `json:"issued"`
+
+	// Sequence numbers the issuer's claims from 1, and Previous is the
+	// ChainHash of the issuer's claim before this one. Both are zero for
+	// claims made before claims were chained.
+	Sequence uint64 `json:"sequence,omitempty"`
+	Previous string `json:"previous,omitempty"`
+
 	ClaimBody  Body      `json:"claim"`
+
+	// Encrypted replaces the claim body of private claims, which is then
+	// empty
+	Encrypted *EncryptedBody `json:"encrypted,omitempty"`
+
 	Proof      Proof     `json:"proof"`
 }
 
@@ -23,6 +41,13 @@ type Body struct {
 	Agent    string       `json:"agent"`
 	Tags     []string     `json:"tags"`
 	Rating   AxiomRating `json:"axiomRating"`
+
+	// Evidence holds the conflicting claims of an equivocation report
+	Evidence []*Claim `json:"evidence,omitempty"`
+
+	// SD lists the digests of selectively disclosable fields removed from
+	// the body
+	SD []string `json:"_sd,omitempty"`
 }
 
 // AxiomRating represents confidence scoring for an axiom
@@ -35,6 +60,29 @@ type AxiomRating struct {
 	Axiom          string  `json:"axiom"`
 }
 
+// UnmarshalJSON accepts confidenceValue both as a JSON number and as a
+// numeric string such as "0.99"
+func (r *AxiomRating) UnmarshalJSON(data []byte) error {
+	type rating AxiomRating
+	aux := struct {
+		*rating
+		ConfidenceValue json.Number `json:"confidenceValue"`
+	}{rating: (*rating)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.ConfidenceValue != "" {
+		value, err := aux.ConfidenceValue.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid confidenceValue %q: %w", aux.ConfidenceValue, err)
+		}
+		r.ConfidenceValue = value
+	}
+	return nil
+}
+
 // Proof represents cryptographic verification of the claim
 type Proof struct {
 	Type        string    `json:"type"`
@@ -42,6 +90,35 @@ type Proof struct {
 	Verifier    Verifier  `json:"verifier"`
 	Domain      string    `json:"domain"`
 	ProofValue  string    `json:"proofValue"`
+
+	// Canonicalization names the algorithm applied before hashing
+	// ("jcs" or "urdna2015"); empty means JCS
+	Canonicalization string `json:"canonicalization,omitempty"`
+
+	// Cryptosuite and ProofPurpose are set for Data Integrity proofs
+	// carried over from imported verifiable credentials
+	Cryptosuite  string `json:"cryptosuite,omitempty"`
+	ProofPurpose string `json:"proofPurpose,omitempty"`
+
+	// CapabilityChain delegates signing from the issuer's root key to the
+	// key that signed the claim
+	CapabilityChain []*delegation.Capability `json:"capabilityChain,omitempty"`
+
+	// Policy, when set, requires co-signatures before the claim takes
+	// effect. It is covered by the issuer's signature.
+	Policy *ThresholdPolicy `json:"policy,omitempty"`
+
+	// CoSignatures are added after issuance and are not covered by the
+	// issuer's signature
+	CoSignatures []CoSignature `json:"coSignatures,omitempty"`
+
+	// Disclosures reveal fields listed in the body's _sd digests. Holders
+	// may drop any of them, so they are not covered by the signature.
+	Disclosures []string `json:"disclosures,omitempty"`
+
+	// Timestamp is a timestamp authority's token over the issuer's
+	// signature, bounding when the claim was created
+	Timestamp *timestamp.Token `json:"timestamp,omitempty"`
 }
 
 // Verifier identifies the entity verifying the claim
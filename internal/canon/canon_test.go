@@ -0,0 +1,87 @@
+package canon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJCS(t *testing.T) {
+	// Example from RFC 8785 section 3.2.2
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "€$\u000F\u000aA'B\"\\\\\"\/",
+		"literals": [null, true, false]
+	}`
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+
+	out, err := JCS([]byte(input))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+}
+
+func TestJCSSortsByUTF16(t *testing.T) {
+	// Example from RFC 8785 section 3.2.3
+	input := `{"€":"Euro Sign","\r":"Carriage Return","דּ":"Hebrew Letter Dalet With Dagesh",` +
+		`"1":"One","😀":"Emoji: Grinning Face","\u0080":"Control","ö":"Latin Small Letter O With Diaeresis"}`
+
+	out, err := JCS([]byte(input))
+	assert.NoError(t, err)
+
+	var values []string
+	for _, part := range strings.Split(string(out), `","`) {
+		values = append(values, part[strings.Index(part, `":"`)+3:])
+	}
+	assert.Equal(t, []string{
+		"Carriage Return",
+		"One",
+		"Control",
+		"Latin Small Letter O With Diaeresis",
+		"Euro Sign",
+		"Emoji: Grinning Face",
+		`Hebrew Letter Dalet With Dagesh"}`,
+	}, values)
+}
+
+func TestRDFIsStableAcrossSerializations(t *testing.T) {
+	a := `{"@context":"https://schema.axios.ai/AxiomaticClaim.jsonld","type":"AxiomaticClaim",
+		"issuer":"did:key:z6Mk","claim":{"@context":"https://schema.axios.ai/","subject":"did:fact:sky",
+		"axiomRating":{"@context":"https://schema.axios.ai/","confidenceValue":0.99}}}`
+	b := `{"claim":{"axiomRating":{"confidenceValue":9.9e-1,"@context":"https://schema.axios.ai/"},
+		"subject":"did:fact:sky","@context":"https://schema.axios.ai/"},"issuer":"did:key:z6Mk",
+		"type":"AxiomaticClaim","@context":"https://schema.axios.ai/AxiomaticClaim.jsonld"}`
+
+	outA, err := RDF([]byte(a))
+	assert.NoError(t, err)
+	outB, err := RDF([]byte(b))
+	assert.NoError(t, err)
+	assert.Equal(t, string(outA), string(outB))
+	assert.Contains(t, string(outA), `<https://schema.axios.ai/confidenceValue> "9.9E-1"^^<http://www.w3.org/2001/XMLSchema#double>`)
+
+	_, err = RDF([]byte(`{"@context":"https://example.com/other.jsonld","name":"x"}`))
+	assert.ErrorIs(t, err, ErrUnknownContext)
+}
+
+func TestCanonicalizeRelabelsBlankNodes(t *testing.T) {
+	p := Term{Kind: IRI, Value: "http://example.com/knows"}
+	name := Term{Kind: IRI, Value: "http://example.com/name"}
+	blank := func(id string) Term { return Term{Kind: BlankNode, Value: id} }
+	literal := func(s string) Term { return Term{Kind: Literal, Value: s} }
+
+	first := []Quad{
+		{Subject: blank("x"), Predicate: p, Object: blank("y")},
+		{Subject: blank("y"), Predicate: p, Object: blank("x")},
+		{Subject: blank("x"), Predicate: name, Object: literal("alice")},
+	}
+	second := []Quad{
+		{Subject: blank("q"), Predicate: name, Object: literal("alice")},
+		{Subject: blank("r"), Predicate: p, Object: blank("q")},
+		{Subject: blank("q"), Predicate: p, Object: blank("r")},
+	}
+
+	out := Canonicalize(first)
+	assert.Equal(t, out, Canonicalize(second))
+	assert.NotContains(t, out, "_:x")
+	assert.Contains(t, out, "_:c14n0")
+}
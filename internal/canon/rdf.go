@@ -0,0 +1,316 @@
+package canon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Vocab is the vocabulary the built-in axia JSON-LD contexts map terms to
+const Vocab = "https://schema.axios.ai/"
+
+const (
+	rdfType       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	rdfLangString = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+	xsdString     = "http://www.w3.org/2001/XMLSchema#string"
+	xsdBoolean    = "http://www.w3.org/2001/XMLSchema#boolean"
+	xsdInteger    = "http://www.w3.org/2001/XMLSchema#integer"
+	xsdDouble     = "http://www.w3.org/2001/XMLSchema#double"
+)
+
+var ErrUnknownContext = errors.New("unknown JSON-LD context")
+
+// knownContexts maps the contexts used by axia documents to their
+// vocabulary. Remote contexts are never fetched, so documents using any
+// other context cannot be converted to RDF.
+var knownContexts = map[string]string{
+	"https://schema.axios.ai/AxiomaticClaim.jsonld": Vocab,
+	"https://schema.axios.ai/":                      Vocab,
+}
+
+// TermKind distinguishes the kinds of RDF terms
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	BlankNode
+	Literal
+)
+
+// Term is an RDF term
+type Term struct {
+	Kind     TermKind
+	Value    string
+	Datatype string
+	Language string
+}
+
+// Quad is an RDF statement; a zero Graph is the default graph
+type Quad struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+	Graph     *Term
+}
+
+// RDF converts a JSON-LD document to RDF and returns its URDNA2015
+// canonical N-Quads serialization
+func RDF(data []byte) ([]byte, error) {
+	quads, err := ToRDF(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(Canonicalize(quads)), nil
+}
+
+// ToRDF converts a JSON-LD document that only uses the built-in axia
+// contexts into an RDF dataset
+func ToRDF(data []byte) ([]Quad, error) {
+	value, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &converter{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, err := c.node(v, ""); err != nil {
+			return nil, err
+		}
+	case []interface{}:
+		for _, elem := range v {
+			obj, ok := elem.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("top-level array must contain objects")
+			}
+			if _, err := c.node(obj, ""); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("JSON-LD document must be an object or array")
+	}
+	return c.quads, nil
+}
+
+type converter struct {
+	quads  []Quad
+	blanks int
+}
+
+func (c *converter) node(obj map[string]interface{}, vocab string) (Term, error) {
+	if ctx, ok := obj["@context"]; ok {
+		var err error
+		if vocab, err = contextVocab(ctx); err != nil {
+			return Term{}, err
+		}
+	}
+	if vocab == "" {
+		return Term{}, fmt.Errorf("%w: document has no @context", ErrUnknownContext)
+	}
+
+	subject := c.newBlank()
+	for _, key := range []string{"@id", "id"} {
+		if id, ok := obj[key].(string); ok {
+			subject = Term{Kind: IRI, Value: expandIRI(id, vocab)}
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := obj[key]
+		switch key {
+		case "@context", "@id", "id":
+			continue
+		case "@type", "type":
+			for _, t := range asArray(value) {
+				s, ok := t.(string)
+				if !ok {
+					return Term{}, fmt.Errorf("type must be a string")
+				}
+				c.emit(subject, Term{Kind: IRI, Value: rdfType}, Term{Kind: IRI, Value: expandIRI(s, vocab)})
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "@") {
+			return Term{}, fmt.Errorf("unsupported JSON-LD keyword %s", key)
+		}
+
+		predicate := Term{Kind: IRI, Value: expandIRI(key, vocab)}
+		for _, elem := range asArray(value) {
+			object, ok, err := c.object(elem, vocab)
+			if err != nil {
+				return Term{}, err
+			}
+			if ok {
+				c.emit(subject, predicate, object)
+			}
+		}
+	}
+	return subject, nil
+}
+
+func (c *converter) object(value interface{}, vocab string) (Term, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return Term{}, false, nil
+	case string:
+		return Term{Kind: Literal, Value: v, Datatype: xsdString}, true, nil
+	case bool:
+		return Term{Kind: Literal, Value: strconv.FormatBool(v), Datatype: xsdBoolean}, true, nil
+	case json.Number:
+		term, err := numberLiteral(v)
+		return term, err == nil, err
+	case map[string]interface{}:
+		if literal, ok := v["@value"]; ok {
+			return valueObject(v, literal)
+		}
+		term, err := c.node(v, vocab)
+		return term, err == nil, err
+	default:
+		return Term{}, false, fmt.Errorf("unsupported JSON-LD value of type %T", value)
+	}
+}
+
+func (c *converter) emit(subject, predicate, object Term) {
+	c.quads = append(c.quads, Quad{Subject: subject, Predicate: predicate, Object: object})
+}
+
+func (c *converter) newBlank() Term {
+	c.blanks++
+	return Term{Kind: BlankNode, Value: "b" + strconv.Itoa(c.blanks-1)}
+}
+
+func contextVocab(ctx interface{}) (string, error) {
+	vocab := ""
+	for _, entry := range asArray(ctx) {
+		url, ok := entry.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: inline contexts are not supported", ErrUnknownContext)
+		}
+		mapped, ok := knownContexts[url]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownContext, url)
+		}
+		vocab = mapped
+	}
+	return vocab, nil
+}
+
+func valueObject(obj map[string]interface{}, literal interface{}) (Term, bool, error) {
+	s, ok := literal.(string)
+	if !ok {
+		return Term{}, false, fmt.Errorf("@value must be a string")
+	}
+	if lang, ok := obj["@language"].(string); ok {
+		return Term{Kind: Literal, Value: s, Datatype: rdfLangString, Language: strings.ToLower(lang)}, true, nil
+	}
+	datatype := xsdString
+	if dt, ok := obj["@type"].(string); ok {
+		datatype = dt
+	}
+	return Term{Kind: Literal, Value: s, Datatype: datatype}, true, nil
+}
+
+// numberLiteral converts a JSON number using the JSON-LD rules: integral
+// values become xsd:integer, all others xsd:double in canonical form
+func numberLiteral(n json.Number) (Term, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return Term{}, fmt.Errorf("%w: %s", ErrInvalidNumber, n)
+	}
+
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return Term{Kind: Literal, Value: strconv.FormatFloat(f, 'f', 0, 64), Datatype: xsdInteger}, nil
+	}
+
+	// Canonical xsd:double as produced by jsonld.js: one leading digit,
+	// trailing zeros trimmed and an unpadded exponent
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'E', 15, 64), "E")
+	mantissa = strings.TrimRight(mantissa, "0")
+	if strings.HasSuffix(mantissa, ".") {
+		mantissa += "0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+	return Term{Kind: Literal, Value: mantissa + "E" + strconv.Itoa(exp), Datatype: xsdDouble}, nil
+}
+
+func expandIRI(value, vocab string) string {
+	if strings.Contains(value, ":") {
+		return value
+	}
+	return vocab + value
+}
+
+func asArray(value interface{}) []interface{} {
+	if arr, ok := value.([]interface{}); ok {
+		return arr
+	}
+	return []interface{}{value}
+}
+
+// serializeTerm writes a term in canonical N-Quads form
+func serializeTerm(t Term) string {
+	switch t.Kind {
+	case IRI:
+		return "<" + t.Value + ">"
+	case BlankNode:
+		return "_:" + t.Value
+	default:
+		s := `"` + escapeLiteral(t.Value) + `"`
+		if t.Language != "" {
+			return s + "@" + t.Language
+		}
+		if t.Datatype != "" && t.Datatype != xsdString {
+			return s + "^^<" + t.Datatype + ">"
+		}
+		return s
+	}
+}
+
+func serializeQuad(q Quad) string {
+	line := serializeTerm(q.Subject) + " " + serializeTerm(q.Predicate) + " " + serializeTerm(q.Object)
+	if q.Graph != nil {
+		line += " " + serializeTerm(*q.Graph)
+	}
+	return line + " .\n"
+}
+
+func escapeLiteral(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,326 @@
+package canon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize relabels the blank nodes of a dataset with the URDNA2015
+// algorithm and returns the sorted canonical N-Quads serialization
+func Canonicalize(quads []Quad) string {
+	c := &canonicalizer{
+		blankQuads: make(map[string][]Quad),
+		canonical:  newIssuer("c14n"),
+		firstHash:  make(map[string]string),
+	}
+
+	for _, q := range quads {
+		for _, t := range blankComponents(q) {
+			c.blankQuads[t.Value] = append(c.blankQuads[t.Value], q)
+		}
+	}
+
+	// Issue canonical identifiers for blank nodes with a unique
+	// first-degree hash, in hash order
+	hashToBlanks := make(map[string][]string)
+	for id := range c.blankQuads {
+		hash := c.hashFirstDegree(id)
+		hashToBlanks[hash] = append(hashToBlanks[hash], id)
+	}
+
+	hashes := sortedKeys(hashToBlanks)
+	var shared []string
+	for _, hash := range hashes {
+		ids := hashToBlanks[hash]
+		if len(ids) > 1 {
+			shared = append(shared, hash)
+			continue
+		}
+		c.canonical.issue(ids[0])
+	}
+
+	// Disambiguate the remaining blank nodes with N-degree hashing
+	for _, hash := range shared {
+		type pathResult struct {
+			hash   string
+			issuer *issuer
+		}
+		var results []pathResult
+
+		ids := append([]string(nil), hashToBlanks[hash]...)
+		sort.Strings(ids)
+		for _, id := range ids {
+			if c.canonical.has(id) {
+				continue
+			}
+			temp := newIssuer("b")
+			temp.issue(id)
+			h, iss := c.hashNDegree(id, temp)
+			results = append(results, pathResult{hash: h, issuer: iss})
+		}
+
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].hash < results[j].hash
+		})
+		for _, result := range results {
+			for _, id := range result.issuer.order {
+				c.canonical.issue(id)
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(quads))
+	for _, q := range quads {
+		lines = append(lines, serializeQuad(c.relabel(q)))
+	}
+	sort.Strings(lines)
+
+	// Drop duplicate statements; a dataset is a set
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if i == 0 || line != lines[i-1] {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "")
+}
+
+type canonicalizer struct {
+	blankQuads map[string][]Quad
+	canonical  *issuer
+	firstHash  map[string]string
+}
+
+func (c *canonicalizer) relabel(q Quad) Quad {
+	relabel := func(t Term) Term {
+		if t.Kind == BlankNode {
+			t.Value = c.canonical.issue(t.Value)
+		}
+		return t
+	}
+
+	out := Quad{
+		Subject:   relabel(q.Subject),
+		Predicate: q.Predicate,
+		Object:    relabel(q.Object),
+	}
+	if q.Graph != nil {
+		g := relabel(*q.Graph)
+		out.Graph = &g
+	}
+	return out
+}
+
+// hashFirstDegree hashes the quads mentioning a blank node, with the node
+// itself labelled _:a and every other blank node _:z
+func (c *canonicalizer) hashFirstDegree(id string) string {
+	if hash, ok := c.firstHash[id]; ok {
+		return hash
+	}
+
+	mask := func(t Term) Term {
+		if t.Kind == BlankNode {
+			if t.Value == id {
+				t.Value = "a"
+			} else {
+				t.Value = "z"
+			}
+		}
+		return t
+	}
+
+	lines := make([]string, 0, len(c.blankQuads[id]))
+	for _, q := range c.blankQuads[id] {
+		masked := Quad{Subject: mask(q.Subject), Predicate: q.Predicate, Object: mask(q.Object)}
+		if q.Graph != nil {
+			g := mask(*q.Graph)
+			masked.Graph = &g
+		}
+		lines = append(lines, serializeQuad(masked))
+	}
+	sort.Strings(lines)
+
+	hash := sha256Hex(strings.Join(lines, ""))
+	c.firstHash[id] = hash
+	return hash
+}
+
+func (c *canonicalizer) hashRelatedBlankNode(related string, q Quad, iss *issuer, position string) string {
+	var identifier string
+	switch {
+	case c.canonical.has(related):
+		identifier = "_:" + c.canonical.issue(related)
+	case iss.has(related):
+		identifier = "_:" + iss.issue(related)
+	default:
+		identifier = c.hashFirstDegree(related)
+	}
+
+	input := position
+	if position != "g" {
+		input += "<" + q.Predicate.Value + ">"
+	}
+	return sha256Hex(input + identifier)
+}
+
+func (c *canonicalizer) hashNDegree(id string, iss *issuer) (string, *issuer) {
+	hashToRelated := make(map[string][]string)
+	for _, q := range c.blankQuads[id] {
+		components := []struct {
+			term     *Term
+			position string
+		}{
+			{&q.Subject, "s"},
+			{&q.Object, "o"},
+			{q.Graph, "g"},
+		}
+		for _, comp := range components {
+			if comp.term == nil || comp.term.Kind != BlankNode || comp.term.Value == id {
+				continue
+			}
+			hash := c.hashRelatedBlankNode(comp.term.Value, q, iss, comp.position)
+			hashToRelated[hash] = append(hashToRelated[hash], comp.term.Value)
+		}
+	}
+
+	var data strings.Builder
+	for _, relatedHash := range sortedKeys(hashToRelated) {
+		data.WriteString(relatedHash)
+
+		chosenPath := ""
+		var chosenIssuer *issuer
+
+		permute(hashToRelated[relatedHash], func(perm []string) {
+			issuerCopy := iss.clone()
+			path := ""
+			var recursion []string
+
+			for _, related := range perm {
+				if c.canonical.has(related) {
+					path += "_:" + c.canonical.issue(related)
+				} else {
+					if !issuerCopy.has(related) {
+						recursion = append(recursion, related)
+					}
+					path += "_:" + issuerCopy.issue(related)
+				}
+				if chosenPath != "" && len(path) >= len(chosenPath) && path > chosenPath {
+					return
+				}
+			}
+
+			for _, related := range recursion {
+				hash, result := c.hashNDegree(related, issuerCopy)
+				path += "_:" + issuerCopy.issue(related)
+				path += "<" + hash + ">"
+				issuerCopy = result
+				if chosenPath != "" && len(path) >= len(chosenPath) && path > chosenPath {
+					return
+				}
+			}
+
+			if chosenPath == "" || path < chosenPath {
+				chosenPath = path
+				chosenIssuer = issuerCopy
+			}
+		})
+
+		data.WriteString(chosenPath)
+		iss = chosenIssuer
+	}
+
+	return sha256Hex(data.String()), iss
+}
+
+// issuer hands out sequential blank node identifiers
+type issuer struct {
+	prefix  string
+	issued  map[string]string
+	order   []string
+	counter int
+}
+
+func newIssuer(prefix string) *issuer {
+	return &issuer{prefix: prefix, issued: make(map[string]string)}
+}
+
+func (i *issuer) has(id string) bool {
+	_, ok := i.issued[id]
+	return ok
+}
+
+func (i *issuer) issue(id string) string {
+	if label, ok := i.issued[id]; ok {
+		return label
+	}
+	label := i.prefix + strconv.Itoa(i.counter)
+	i.counter++
+	i.issued[id] = label
+	i.order = append(i.order, id)
+	return label
+}
+
+func (i *issuer) clone() *issuer {
+	c := &issuer{
+		prefix:  i.prefix,
+		issued:  make(map[string]string, len(i.issued)),
+		order:   append([]string(nil), i.order...),
+		counter: i.counter,
+	}
+	for k, v := range i.issued {
+		c.issued[k] = v
+	}
+	return c
+}
+
+func blankComponents(q Quad) []Term {
+	var terms []Term
+	if q.Subject.Kind == BlankNode {
+		terms = append(terms, q.Subject)
+	}
+	if q.Object.Kind == BlankNode && q.Object.Value != q.Subject.Value {
+		terms = append(terms, q.Object)
+	}
+	if q.Graph != nil && q.Graph.Kind == BlankNode &&
+		q.Graph.Value != q.Subject.Value && q.Graph.Value != q.Object.Value {
+		terms = append(terms, *q.Graph)
+	}
+	return terms
+}
+
+// permute calls fn with every permutation of ids
+func permute(ids []string, fn func([]string)) {
+	perm := append([]string(nil), ids...)
+	sort.Strings(perm)
+
+	var generate func(k int)
+	generate = func(k int) {
+		if k == len(perm) {
+			fn(append([]string(nil), perm...))
+			return
+		}
+		for i := k; i < len(perm); i++ {
+			perm[k], perm[i] = perm[i], perm[k]
+			generate(k + 1)
+			perm[k], perm[i] = perm[i], perm[k]
+		}
+	}
+	generate(0)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetCoSignCmd returns the cosign subcommand
+func GetCoSignCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cosign [claim-file|-]",
+		Short: "Add a co-signature to a claim",
+		Long: `Verify a claim and co-sign it with the keystore key of --agent. The claim
+is printed with the co-signature appended, ready for the next signer or for
+submission once its threshold policy is met.`,
+		Args:         cobra.ExactArgs(1),
+		Annotations:  map[string]string{OfflineAnnotation: "true"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agent, _ := cmd.Flags().GetString("agent")
+			custodians, _ := cmd.Flags().GetStringSlice("custodian")
+			resolver, err := NewCustodialResolver(custodians)
+			if err != nil {
+				return err
+			}
+
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+			claims, err := axiom.ReadClaims(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			if len(claims) != 1 {
+				return fmt.Errorf("expected one claim in %s, found %d", args[0], len(claims))
+			}
+			claim := claims[0]
+
+			if err := axiom.VerifyClaim(context.Background(), resolver, claim); err != nil {
+				return fmt.Errorf("refusing to co-sign invalid claim: %w", err)
+			}
+
+			key, err := UnlockAgentKey(cmd, logger, agent)
+			if err != nil {
+				return err
+			}
+
+			manager := axiom.NewManager(logger)
+			manager.SetResolver(resolver)
+			manager.RegisterSigner(agent, key)
+			if err := manager.CoSign(claim, agent); err != nil {
+				return err
+			}
+
+			if !axiom.ThresholdMet(claim) {
+				fmt.Fprintf(os.Stderr, "Claim still needs %d of %v\n", claim.Proof.Policy.Threshold, claim.Proof.Policy.Signers)
+			}
+			return printJSON(claim)
+		},
+	}
+
+	cmd.Flags().String("agent", "", "Agent co-signing the claim")
+	cmd.MarkFlagRequired("agent")
+	cmd.Flags().StringSlice("custodian", nil, custodianUsage)
+	return cmd
+}
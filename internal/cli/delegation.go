@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/axia/axia-cli/internal/delegation"
+	"github.com/axia/axia-cli/internal/did"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ReadCapabilities reads a capability chain printed by "keys delegate"
+func ReadCapabilities(path string) ([]*delegation.Capability, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*delegation.Capability
+	if err := json.Unmarshal(data, &chain); err != nil {
+		return nil, fmt.Errorf("invalid capability chain: %w", err)
+	}
+	return chain, nil
+}
+
+// ReadRotation reads a key rotation printed by "keys rotate"
+func ReadRotation(path string) (*delegation.Rotation, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotation delegation.Rotation
+	if err := json.Unmarshal(data, &rotation); err != nil {
+		return nil, fmt.Errorf("invalid key rotation: %w", err)
+	}
+	return &rotation, nil
+}
+
+func getKeysDelegateCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegate [name] [delegate-did]",
+		Short: "Let another key sign claims for an agent",
+		Long: `Sign a capability that lets the delegate key, given as a did:key, sign
+claims for the agent of key [name] within a scope and time window.
+
+With --parent, [name] must be the delegate of the parent chain and the new
+capability, which may only narrow it, is appended to the chain. The chain is
+printed as JSON for "axios claim --capability" on the delegate's host.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			subjects, _ := cmd.Flags().GetStringSlice("subjects")
+			valid, _ := cmd.Flags().GetDuration("valid")
+			parentPath, _ := cmd.Flags().GetString("parent")
+
+			delegate, err := did.PublicKeyFromDIDKey(args[1])
+			if err != nil {
+				return fmt.Errorf("delegate must be an Ed25519 did:key: %w", err)
+			}
+
+			scope := delegation.Scope{Tags: tags, Subjects: subjects}
+			if cmd.Flags().Changed("max-confidence") {
+				maxConfidence, _ := cmd.Flags().GetFloat64("max-confidence")
+				scope.MaxConfidence = &maxConfidence
+			}
+
+			var chain []*delegation.Capability
+			var parent *delegation.Capability
+			if parentPath != "" {
+				if chain, err = ReadCapabilities(parentPath); err != nil {
+					return err
+				}
+				if len(chain) > 0 {
+					parent = chain[len(chain)-1]
+				}
+			}
+
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+			key, err := ks.Get(args[0])
+			if err != nil {
+				return err
+			}
+			passphrase, err := ReadPassphrase(cmd)
+			if err != nil {
+				return err
+			}
+			signer, err := key.Unlock(passphrase)
+			if err != nil {
+				return fmt.Errorf("failed to unlock key '%s': %w", key.Name, err)
+			}
+
+			now := time.Now()
+			capability, err := delegation.Delegate(key.Agent, signer, delegate, scope, now, now.Add(valid), parent)
+			if err != nil {
+				return err
+			}
+			return printJSON(append(chain, capability))
+		},
+	}
+
+	cmd.Flags().StringSlice("tags", nil, "Tags the delegate may use (default any)")
+	cmd.Flags().StringSlice("subjects", nil, "Subjects the delegate may make claims about (default any)")
+	cmd.Flags().Float64("max-confidence", 1.0, "Highest confidence the delegate may claim (default any)")
+	cmd.Flags().Duration("valid", 24*time.Hour, "How long the delegation is valid")
+	cmd.Flags().String("parent", "", "Capability chain to extend")
+	return cmd
+}
+
+func getKeysRotateCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate [name] [new-name]",
+		Short: "Replace an agent key with a new one",
+		Long: `Generate a new key for the agent of key [name], sign a rotation to it with
+the old key and retire the old key. Record the printed rotation on nodes with
+"axios rotation"; DID issuers must also list the new key in their document.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+			key, err := ks.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if key.Retired != nil {
+				return fmt.Errorf("key '%s' was already rotated", key.Name)
+			}
+			passphrase, err := ReadPassphrase(cmd)
+			if err != nil {
+				return err
+			}
+			previous, err := key.Unlock(passphrase)
+			if err != nil {
+				return fmt.Errorf("failed to unlock key '%s': %w", key.Name, err)
+			}
+
+			next, err := ks.Generate(args[1], key.Agent, passphrase)
+			if err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+			nextPair, err := next.Unlock(passphrase)
+			if err != nil {
+				return err
+			}
+
+			rotation, err := delegation.Rotate(key.Agent, previous, nextPair.PublicKey(), time.Now())
+			if err != nil {
+				return err
+			}
+			if err := ks.Retire(key.Name); err != nil {
+				return err
+			}
+			return printJSON(rotation)
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetDiscloseCmd returns the disclose subcommand
+func GetDiscloseCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disclose [claim-file|-]",
+		Short: "Present a claim with a subset of its concealed fields",
+		Long: `Verify a selectively disclosed claim and print it carrying only the
+disclosures of --fields. Fields left out stay concealed; the presented claim
+still verifies against the issuer's signature.`,
+		Args:         cobra.ExactArgs(1),
+		Annotations:  map[string]string{OfflineAnnotation: "true"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields, _ := cmd.Flags().GetStringSlice("fields")
+			custodians, _ := cmd.Flags().GetStringSlice("custodian")
+			resolver, err := NewCustodialResolver(custodians)
+			if err != nil {
+				return err
+			}
+
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+			claims, err := axiom.ReadClaims(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			if len(claims) != 1 {
+				return fmt.Errorf("expected one claim in %s, found %d", args[0], len(claims))
+			}
+
+			if err := axiom.VerifyClaim(context.Background(), resolver, claims[0]); err != nil {
+				return fmt.Errorf("refusing to present invalid claim: %w", err)
+			}
+
+			presented, err := axiom.Present(claims[0], fields)
+			if err != nil {
+				return err
+			}
+			logger.WithField("fields", fields).Debug("Presenting claim")
+			return printJSON(presented)
+		},
+	}
+
+	cmd.Flags().StringSlice("fields", nil, "Concealed fields to disclose (axiom, tags, confidenceValue)")
+	cmd.Flags().StringSlice("custodian", nil, custodianUsage)
+	return cmd
+}
@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/axia/axia-cli/internal/crypto"
+	"github.com/axia/axia-cli/internal/did"
+	"github.com/axia/axia-cli/internal/keystore"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// PassphraseEnv names the environment variable holding the keystore passphrase
+const PassphraseEnv = "AXIA_KEYSTORE_PASSPHRASE"
+
+// OfflineAnnotation marks commands that run without the secret key or database
+const OfflineAnnotation = "axia.offline"
+
+// IsOffline reports whether cmd or one of its parents is marked offline
+func IsOffline(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[OfflineAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// AddKeystoreFlags registers the persistent flags used to locate and unlock
+// the keystore
+func AddKeystoreFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.String("keystore", keystore.DefaultDir(), "Directory holding encrypted agent keys")
+	flags.String("passphrase-file", "", "File containing the keystore passphrase (default $"+PassphraseEnv+")")
+}
+
+// OpenKeystore opens the keystore selected by the command flags
+func OpenKeystore(cmd *cobra.Command, logger *logrus.Logger) (*keystore.Keystore, error) {
+	dir, _ := cmd.Flags().GetString("keystore")
+	return keystore.New(dir, logger)
+}
+
+// ReadPassphrase returns the keystore passphrase from --passphrase-file or
+// the environment
+func ReadPassphrase(cmd *cobra.Command) (string, error) {
+	if path, _ := cmd.Flags().GetString("passphrase-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	return "", fmt.Errorf("no passphrase given: set %s or use --passphrase-file", PassphraseEnv)
+}
+
+// UnlockAgentKey finds the keystore key belonging to agent and unlocks it.
+// It returns keystore.ErrKeyNotFound if the agent has no stored key.
+func UnlockAgentKey(cmd *cobra.Command, logger *logrus.Logger, agent string) (*crypto.KeyPair, error) {
+	ks, err := OpenKeystore(cmd, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ks.FindByAgent(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := ReadPassphrase(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := key.Unlock(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock key '%s': %w", key.Name, err)
+	}
+	return pair, nil
+}
+
+// AgentKeyID returns the did:key of the keystore key belonging to agent
+// without unlocking it. It returns keystore.ErrKeyNotFound if the agent
+// has no stored key.
+func AgentKeyID(cmd *cobra.Command, logger *logrus.Logger, agent string) (string, error) {
+	ks, err := OpenKeystore(cmd, logger)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := ks.FindByAgent(agent)
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, err := hex.DecodeString(key.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("corrupted public key of key '%s': %w", key.Name, err)
+	}
+	return did.FromPublicKey(publicKey), nil
+}
+
+// GetKeysCmd returns the keys command group
+func GetKeysCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "keys",
+		Short:       "Manage agent signing keys",
+		Annotations: map[string]string{OfflineAnnotation: "true"},
+	}
+
+	cmd.AddCommand(
+		getKeysGenerateCmd(logger),
+		getKeysListCmd(logger),
+		getKeysShowCmd(logger),
+		getKeysExportCmd(logger),
+		getKeysImportCmd(logger),
+		getKeysDeleteCmd(logger),
+		getKeysDelegateCmd(logger),
+		getKeysRotateCmd(logger),
+	)
+	return cmd
+}
+
+func getKeysGenerateCmd(logger *logrus.Logger) *cobra.Command {
+	var agent string
+
+	cmd := &cobra.Command{
+		Use:   "generate [name]",
+		Short: "Generate a new agent signing key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+			passphrase, err := ReadPassphrase(cmd)
+			if err != nil {
+				return err
+			}
+
+			key, err := ks.Generate(args[0], agent, passphrase)
+			if err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+
+			fmt.Printf("Generated key '%s' for agent %s\n", key.Name, key.Agent)
+			return printKey(key)
+		},
+	}
+
+	cmd.Flags().StringVar(&agent, "agent", "", "DID or URL of the agent the key signs for (default: the key's did:key)")
+	return cmd
+}
+
+func getKeysListCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List stored agent keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			keys, err := ks.List()
+			if err != nil {
+				return err
+			}
+
+			if len(keys) == 0 {
+				fmt.Println("No keys found.")
+				return nil
+			}
+
+			for _, key := range keys {
+				if key.Retired != nil {
+					fmt.Printf("%-20s %s (retired)\n", key.Name, key.Agent)
+					continue
+				}
+				fmt.Printf("%-20s %s\n", key.Name, key.Agent)
+			}
+			return nil
+		},
+	}
+}
+
+func getKeysShowCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show the public details of a key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			key, err := ks.Get(args[0])
+			if err != nil {
+				return err
+			}
+			return printKey(key)
+		},
+	}
+}
+
+func getKeysExportCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [name]",
+		Short: "Print the encrypted key file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			data, err := ks.Export(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
+
+func getKeysImportCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [file|-]",
+		Short: "Import an encrypted key file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+
+			key, err := ks.Import(data)
+			if err != nil {
+				return fmt.Errorf("failed to import key: %w", err)
+			}
+
+			fmt.Printf("Imported key '%s' for agent %s\n", key.Name, key.Agent)
+			return nil
+		},
+	}
+}
+
+func getKeysDeleteCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a stored key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := OpenKeystore(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			if err := ks.Delete(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("Deleted key '%s'\n", args[0])
+			return nil
+		},
+	}
+}
+
+func printKey(key *keystore.EncryptedKey) error {
+	publicKey, err := hex.DecodeString(key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("corrupted public key: %w", err)
+	}
+
+	fmt.Printf("  Name:     %s\n", key.Name)
+	fmt.Printf("  Agent:    %s\n", key.Agent)
+	fmt.Printf("  DID:      %s\n", did.FromPublicKey(publicKey))
+	fmt.Printf("  Verifier: %s\n", axiom.KeyID(publicKey))
+	fmt.Printf("  Created:  %s\n", key.Created.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// readInput reads a file, or stdin when path is "-"
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/axia/axia-cli/internal/translog"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetLogCmd returns the log command group. The log is opened by the root
+// command, so it is passed as a getter.
+func GetLogCmd(logger *logrus.Logger, getLog func() *translog.Log) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Inspect the claim transparency log",
+	}
+
+	cmd.AddCommand(
+		getLogHeadCmd(getLog),
+		getLogProveCmd(logger, getLog),
+		getLogConsistencyCmd(getLog),
+	)
+	return cmd
+}
+
+func getLogHeadCmd(getLog func() *translog.Log) *cobra.Command {
+	return &cobra.Command{
+		Use:   "head",
+		Short: "Print the signed tree head",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sth, err := getLog().SignedHead()
+			if err != nil {
+				return err
+			}
+			return printJSON(sth)
+		},
+	}
+}
+
+func getLogProveCmd(logger *logrus.Logger, getLog func() *translog.Log) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prove [file|-]",
+		Short: "Print inclusion proofs for claims",
+		Long: `Print an inclusion proof for each claim in the input, against the tree
+of --size entries or the current tree.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			size, _ := cmd.Flags().GetUint64("size")
+
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+			claims, err := axiom.ReadClaims(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+
+			proofs := make([]*translog.InclusionProof, 0, len(claims))
+			for i, claim := range claims {
+				leaf, err := translog.ClaimLeaf(claim)
+				if err != nil {
+					return err
+				}
+
+				proof, err := getLog().ProveInclusion(translog.LeafHash(leaf), size)
+				if err != nil {
+					return fmt.Errorf("claim #%d: %w", i+1, err)
+				}
+				if err := proof.Verify(); err != nil {
+					logger.WithError(err).Error("Log produced an invalid inclusion proof")
+					return err
+				}
+				proofs = append(proofs, proof)
+			}
+
+			if len(proofs) == 1 {
+				return printJSON(proofs[0])
+			}
+			return printJSON(proofs)
+		},
+	}
+	cmd.Flags().Uint64("size", 0, "Tree size to prove against (default current size)")
+	return cmd
+}
+
+func getLogConsistencyCmd(getLog func() *translog.Log) *cobra.Command {
+	return &cobra.Command{
+		Use:   "consistency [first-size] [second-size]",
+		Short: "Print a consistency proof between two tree sizes",
+		Long: `Print a proof that the tree of second-size entries (default the current
+tree) is an append-only extension of the tree of first-size entries.`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			first, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid first size: %w", err)
+			}
+			var second uint64
+			if len(args) == 2 {
+				if second, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+					return fmt.Errorf("invalid second size: %w", err)
+				}
+			}
+
+			proof, err := getLog().ProveConsistency(first, second)
+			if err != nil {
+				return err
+			}
+			return printJSON(proof)
+		},
+	}
+}
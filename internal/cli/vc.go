@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/axia/axia-cli/internal/crypto"
+	"github.com/axia/axia-cli/internal/vc"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetVCCmd returns the vc command group for converting claims to and from
+// W3C Verifiable Credentials
+func GetVCCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "vc",
+		Short:       "Convert claims to and from Verifiable Credentials",
+		Annotations: map[string]string{OfflineAnnotation: "true"},
+	}
+
+	cmd.AddCommand(
+		getVCExportCmd(logger),
+		getVCImportCmd(logger),
+	)
+	return cmd
+}
+
+func getVCExportCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [file|-]",
+		Short: "Export signed claims as VC 2.0 credentials",
+		Long: `Convert claims to VC Data Model 2.0 credentials with an eddsa-jcs-2022
+Data Integrity proof. Each credential is signed with the keystore key of the
+claim's issuer, which must be the key that signed the claim.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+
+			claims, err := axiom.ReadClaims(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			if len(claims) == 0 {
+				return fmt.Errorf("no claims found in %s", args[0])
+			}
+
+			signers := make(map[string]*crypto.KeyPair)
+			creds := make([]*vc.Credential, 0, len(claims))
+			for i, claim := range claims {
+				signer, ok := signers[claim.Issuer]
+				if !ok {
+					if signer, err = UnlockAgentKey(cmd, logger, claim.Issuer); err != nil {
+						return fmt.Errorf("claim #%d: %w", i+1, err)
+					}
+					signers[claim.Issuer] = signer
+				}
+
+				cred, err := vc.Export(claim, signer)
+				if err != nil {
+					return fmt.Errorf("claim #%d: %w", i+1, err)
+				}
+				creds = append(creds, cred)
+			}
+
+			if len(creds) == 1 {
+				return printJSON(creds[0])
+			}
+			return printJSON(creds)
+		},
+	}
+}
+
+func getVCImportCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [file|-]",
+		Short: "Convert VC 2.0 credentials to claims",
+		Long: `Verify VC Data Model 2.0 axiomatic claim credentials and print them as
+claims. Credentials must carry an eddsa-jcs-2022 proof from a did:key
+verification method, and DID issuers must list that key.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+
+			creds, err := vc.ReadCredentials(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			if len(creds) == 0 {
+				return fmt.Errorf("no credentials found in %s", args[0])
+			}
+
+			resolver := NewResolver()
+			claims := make([]*axiom.Claim, 0, len(creds))
+			for i, cred := range creds {
+				claim, err := vc.ToClaim(cred)
+				if err != nil {
+					return fmt.Errorf("credential #%d: %w", i+1, err)
+				}
+				if err := axiom.VerifyClaim(context.Background(), resolver, claim); err != nil {
+					return fmt.Errorf("credential #%d: %w", i+1, err)
+				}
+				logger.WithField("issuer", claim.Issuer).Debug("Imported credential")
+				claims = append(claims, claim)
+			}
+
+			if len(claims) == 1 {
+				return printJSON(claims[0])
+			}
+			return printJSON(claims)
+		},
+	}
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/axia/axia-cli/internal/did"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewResolver returns the DID resolver used when verifying claims: did:key
+// is resolved locally and did:web over HTTPS
+func NewResolver() did.Resolver {
+	registry := did.NewRegistry()
+	registry.Register(did.MethodWeb, did.NewWebResolver(nil))
+	return registry
+}
+
+// custodianUsage describes the --custodian flag of commands that verify
+// claims
+const custodianUsage = "name=did:key binding an issuer that is not a DID, or all names with a prefix ending in a colon, to its custodian's key"
+
+// NewCustodialResolver returns NewResolver with issuers that are not DIDs
+// bound to custodian keys, given as name=did:key pairs
+func NewCustodialResolver(bindings []string) (*did.Custodians, error) {
+	custodians := did.NewCustodians(NewResolver())
+	for _, binding := range bindings {
+		name, key, ok := strings.Cut(binding, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid custodian %q, expected name=did:key", binding)
+		}
+		publicKey, err := did.PublicKeyFromDIDKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custodian key for %s: %w", name, err)
+		}
+		custodians.Register(name, publicKey)
+	}
+	return custodians, nil
+}
+
+// GetVerifyCmd returns the verify subcommand
+func GetVerifyCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [file|-]",
+		Short: "Verify claims offline",
+		Long: `Verify a claim, a JSON array of claims, a JSONL stream of claims or
+compact JWT-encoded claims. Each claim's proof is recomputed and its issuer
+binding checked; issuers that are not DIDs, such as twitter: reporters, must
+be signed by a --custodian of theirs. Timestamp tokens are checked against the claim; with
+--timestamp-authority, claims must also carry a token from one of the listed
+authorities. The command exits with a non-zero status if any claim fails.`,
+		Args:         cobra.ExactArgs(1),
+		Annotations:  map[string]string{OfflineAnnotation: "true"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			authorities, _ := cmd.Flags().GetStringSlice("timestamp-authority")
+			custodians, _ := cmd.Flags().GetStringSlice("custodian")
+			resolver, err := NewCustodialResolver(custodians)
+			if err != nil {
+				return err
+			}
+
+			data, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+
+			claims, err := axiom.ReadClaims(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			if len(claims) == 0 {
+				return fmt.Errorf("no claims found in %s", args[0])
+			}
+
+			failed := 0
+			for i, claim := range claims {
+				err := axiom.VerifyClaim(context.Background(), resolver, claim)
+				// Equivocation reports must carry evidence that proves them
+				if err == nil && len(claim.ClaimBody.Evidence) > 0 {
+					err = axiom.VerifyEquivocationClaim(context.Background(), resolver, claim)
+				}
+				if err == nil && len(authorities) > 0 {
+					err = axiom.RequireTimestamp(claim, authorities)
+				}
+				if err != nil {
+					failed++
+					fmt.Printf("[FAIL] #%d %s -> %s: %v\n", i+1, claim.Issuer, claim.ClaimBody.Subject, err)
+					logger.WithError(err).WithField("index", i+1).Debug("Claim failed verification")
+					continue
+				}
+
+				subject, note := claim.ClaimBody.Subject, ""
+				switch {
+				case !axiom.ThresholdMet(claim):
+					note = " (awaiting co-signatures)"
+				case claim.IsPrivate():
+					subject = "(private)"
+				case claim.IsSelective():
+					note = fmt.Sprintf(" (%d of %d concealed fields disclosed)", len(claim.Proof.Disclosures), len(claim.ClaimBody.SD))
+				}
+				fmt.Printf("[PASS] #%d %s -> %s%s\n", i+1, claim.Issuer, subject, note)
+				if token := claim.Proof.Timestamp; token != nil {
+					fmt.Printf("       timestamped %s by %s\n", token.Time.Format(time.RFC3339), token.Authority)
+				}
+			}
+
+			fmt.Printf("\n%d of %d claims verified\n", len(claims)-failed, len(claims))
+			if failed > 0 {
+				return fmt.Errorf("%d of %d claims failed verification", failed, len(claims))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("timestamp-authority", nil, "did:key of a trusted timestamp authority; claims must carry its token")
+	cmd.Flags().StringSlice("custodian", nil, custodianUsage)
+	return cmd
+}
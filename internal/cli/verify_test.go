@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axia/axia-cli/internal/axiom"
+	"github.com/axia/axia-cli/internal/crypto"
+	"github.com/axia/axia-cli/internal/did"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCmd(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	issuer := did.FromPublicKey(key.PublicKey())
+	manager := axiom.NewManager(logger)
+	manager.RegisterSigner(issuer, key)
+
+	sign := func(subject string) *axiom.Claim {
+		claim, err := manager.CreateClaim(issuer, subject, "Sky is blue", 0.9, nil)
+		assert.NoError(t, err)
+		return claim
+	}
+	encode := func(claims ...*axiom.Claim) []string {
+		lines := make([]string, 0, len(claims))
+		for _, claim := range claims {
+			data, err := json.Marshal(claim)
+			assert.NoError(t, err)
+			lines = append(lines, string(data))
+		}
+		return lines
+	}
+
+	first, second := sign("did:fact:sky"), sign("did:fact:sea")
+	token, err := manager.EncodeJWT(first, time.Hour)
+	assert.NoError(t, err)
+	tampered := sign("did:fact:grass")
+	tampered.ClaimBody.Rating.ConfidenceValue = 0.1
+
+	// An issuer that is not a DID verifies against its custodian's key
+	custodian, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	manager.RegisterSigner("agent:alice", custodian)
+	custodied, err := manager.CreateClaim("agent:alice", "did:fact:sky", "Sky is blue", 0.9, nil)
+	assert.NoError(t, err)
+	binding := "agent:alice=" + did.FromPublicKey(custodian.PublicKey())
+
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+		fails bool
+	}{
+		{name: "single claim", input: encode(first)[0]},
+		{name: "array", input: "[" + strings.Join(encode(first, second), ",") + "]"},
+		{name: "jsonl", input: strings.Join(encode(first, second), "\n")},
+		{name: "jwt", input: token},
+		{name: "tampered claim", input: encode(tampered)[0], fails: true},
+		{name: "one tampered claim of several", input: strings.Join(encode(first, tampered, second), "\n"), fails: true},
+		{name: "empty input", input: "", fails: true},
+		{name: "custodian", input: encode(custodied)[0], args: []string{"--custodian", binding}},
+		{name: "issuer without custodian", input: encode(custodied)[0], fails: true},
+		{name: "missing timestamp", input: encode(first)[0], args: []string{"--timestamp-authority", "did:key:z6MkAuthority"}, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "claims")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.input), 0600))
+
+			cmd := GetVerifyCmd(logger)
+			cmd.SetArgs(append(tt.args, path))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			err := cmd.Execute()
+			if tt.fails {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Multicodec content types of CIDs
+const (
+	CodecRaw     = 0x55
+	CodecDagPB   = 0x70
+	CodecDagCBOR = 0x71
+)
+
+var (
+	ErrInvalidCID  = errors.New("invalid CID")
+	ErrCIDMismatch = errors.New("content does not match CID")
+)
+
+// CID is a content identifier as used by IPFS
+type CID struct {
+	Version   uint64
+	Codec     uint64
+	Multihash []byte
+}
+
+// NewCID returns the CIDv1 of raw content hashed with sha2-256, the
+// identifier IPFS assigns to content stored as a single raw block
+func NewCID(data []byte) *CID {
+	algorithm, _ := LookupHash(HashSHA2_256)
+	return &CID{Version: 1, Codec: CodecRaw, Multihash: algorithm.Sum(data)}
+}
+
+// ParseCID parses a CIDv0 (Qm...) or a base32 or base58btc CIDv1
+func ParseCID(s string) (*CID, error) {
+	// CIDv0 is a bare base58btc sha2-256 multihash of a dag-pb node
+	if len(s) == 46 && s[:2] == "Qm" {
+		mh, err := DecodeBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+		}
+		return &CID{Version: 0, Codec: CodecDagPB, Multihash: mh}, nil
+	}
+
+	data, err := DecodeMultibase(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	version, n := binary.Uvarint(data)
+	if n <= 0 || version != 1 {
+		return nil, fmt.Errorf("%w: unsupported version", ErrInvalidCID)
+	}
+	codec, m := binary.Uvarint(data[n:])
+	if m <= 0 {
+		return nil, ErrInvalidCID
+	}
+
+	mh := data[n+m:]
+	if _, _, err := DecodeMultihash(mh); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	return &CID{Version: 1, Codec: codec, Multihash: mh}, nil
+}
+
+// String encodes the CID: CIDv0 in base58btc, CIDv1 in base32
+func (c *CID) String() string {
+	if c.Version == 0 {
+		return EncodeBase58(c.Multihash)
+	}
+	data := binary.AppendUvarint(nil, c.Version)
+	data = binary.AppendUvarint(data, c.Codec)
+	return EncodeMultibaseBase32(append(data, c.Multihash...))
+}
+
+// Verify checks that data is the content of a raw CID. Content of other
+// codecs is chunked into IPFS nodes and cannot be checked directly.
+func (c *CID) Verify(data []byte) error {
+	if c.Codec != CodecRaw {
+		return fmt.Errorf("%w: codec 0x%x is not raw", ErrInvalidCID, c.Codec)
+	}
+
+	algorithm, _, err := DecodeMultihash(c.Multihash)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(algorithm.Sum(data), c.Multihash) {
+		return ErrCIDMismatch
+	}
+	return nil
+}
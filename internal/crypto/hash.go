@@ -0,0 +1,138 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// Hash algorithm names, as in the multicodec table
+const (
+	HashSHA2_256   = "sha2-256"
+	HashSHA3_256   = "sha3-256"
+	HashBLAKE2b256 = "blake2b-256"
+	HashBLAKE3     = "blake3"
+)
+
+// DefaultHash is the hash algorithm used for new proofs unless configured
+// otherwise
+const DefaultHash = HashSHA3_256
+
+var (
+	ErrUnsupportedHash  = errors.New("unsupported hash algorithm")
+	ErrInvalidMultihash = errors.New("invalid multihash")
+)
+
+// HashAlgorithm is a hash function with its multicodec code
+type HashAlgorithm struct {
+	Name string
+	Code uint64
+	New  func() hash.Hash
+}
+
+var (
+	hashMu         sync.RWMutex
+	hashAlgorithms = make(map[string]*HashAlgorithm)
+	hashCodes      = make(map[uint64]*HashAlgorithm)
+	defaultHash    = DefaultHash
+)
+
+func init() {
+	RegisterHash(&HashAlgorithm{Name: HashSHA2_256, Code: 0x12, New: sha256.New})
+	RegisterHash(&HashAlgorithm{Name: HashSHA3_256, Code: 0x16, New: sha3.New256})
+	RegisterHash(&HashAlgorithm{Name: HashBLAKE2b256, Code: 0xb220, New: func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	}})
+	RegisterHash(&HashAlgorithm{Name: HashBLAKE3, Code: 0x1e, New: NewBLAKE3})
+}
+
+// RegisterHash makes a hash algorithm available by name and multihash code
+func RegisterHash(algorithm *HashAlgorithm) {
+	hashMu.Lock()
+	defer hashMu.Unlock()
+	hashAlgorithms[algorithm.Name] = algorithm
+	hashCodes[algorithm.Code] = algorithm
+}
+
+// LookupHash returns the registered hash algorithm with the given name
+func LookupHash(name string) (*HashAlgorithm, error) {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+
+	algorithm, ok := hashAlgorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedHash, name)
+	}
+	return algorithm, nil
+}
+
+// HashAlgorithms returns the names of all registered hash algorithms
+func HashAlgorithms() []string {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+
+	names := make([]string, 0, len(hashAlgorithms))
+	for name := range hashAlgorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDefaultHash selects the hash algorithm of new proof generators. An
+// empty name selects DefaultHash.
+func SetDefaultHash(name string) error {
+	if name == "" {
+		name = DefaultHash
+	}
+	if _, err := LookupHash(name); err != nil {
+		return err
+	}
+
+	hashMu.Lock()
+	defer hashMu.Unlock()
+	defaultHash = name
+	return nil
+}
+
+// Sum hashes data and returns the digest as a multihash
+func (a *HashAlgorithm) Sum(data []byte) []byte {
+	h := a.New()
+	h.Write(data)
+	return EncodeMultihash(a.Code, h.Sum(nil))
+}
+
+// EncodeMultihash prefixes a digest with its hash code and length
+func EncodeMultihash(code uint64, digest []byte) []byte {
+	mh := binary.AppendUvarint(nil, code)
+	mh = binary.AppendUvarint(mh, uint64(len(digest)))
+	return append(mh, digest...)
+}
+
+// DecodeMultihash splits a multihash into the hash algorithm and digest
+func DecodeMultihash(mh []byte) (*HashAlgorithm, []byte, error) {
+	code, n := binary.Uvarint(mh)
+	if n <= 0 {
+		return nil, nil, ErrInvalidMultihash
+	}
+	length, m := binary.Uvarint(mh[n:])
+	if m <= 0 || uint64(len(mh)-n-m) != length {
+		return nil, nil, ErrInvalidMultihash
+	}
+
+	hashMu.RLock()
+	algorithm, ok := hashCodes[code]
+	hashMu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: multihash code 0x%x", ErrUnsupportedHash, code)
+	}
+	return algorithm, mh[n+m:], nil
+}
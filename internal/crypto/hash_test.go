@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestBLAKE3(t *testing.T) {
+	// Vectors from the BLAKE3 reference test_vectors.json, whose inputs
+	// repeat the bytes 0..250
+	input := func(n int) []byte {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+		return data
+	}
+
+	tests := []struct {
+		input []byte
+		want  string
+	}{
+		{nil, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{[]byte("abc"), "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+		{input(1024), "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{input(1025), "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+	}
+	for _, tt := range tests {
+		h := NewBLAKE3()
+		h.Write(tt.input)
+		assert.Equal(t, tt.want, hex.EncodeToString(h.Sum(nil)), "input length %d", len(tt.input))
+	}
+
+	// Writing in pieces gives the same digest
+	data := input(5000)
+	h := NewBLAKE3()
+	for len(data) > 0 {
+		n := 333
+		if n > len(data) {
+			n = len(data)
+		}
+		h.Write(data[:n])
+		data = data[n:]
+	}
+	whole := NewBLAKE3()
+	whole.Write(input(5000))
+	assert.Equal(t, whole.Sum(nil), h.Sum(nil))
+}
+
+func TestMultihash(t *testing.T) {
+	for _, name := range HashAlgorithms() {
+		algorithm, err := LookupHash(name)
+		assert.NoError(t, err)
+
+		mh := algorithm.Sum([]byte("axia"))
+		decoded, digest, err := DecodeMultihash(mh)
+		assert.NoError(t, err)
+		assert.Equal(t, name, decoded.Name)
+		assert.Len(t, digest, 32)
+	}
+
+	_, err := LookupHash("md5")
+	assert.ErrorIs(t, err, ErrUnsupportedHash)
+
+	_, _, err = DecodeMultihash([]byte{0x12, 0x20, 0x01})
+	assert.ErrorIs(t, err, ErrInvalidMultihash)
+}
+
+func TestSignProofHashAlgorithms(t *testing.T) {
+	key, err := GenerateKeyPair()
+	assert.NoError(t, err)
+	data := map[string]string{"subject": "axia"}
+
+	for _, name := range HashAlgorithms() {
+		pg := NewProofGenerator()
+		assert.NoError(t, pg.SetHashAlgorithm(name))
+
+		proof, err := pg.SignProof(data, key)
+		assert.NoError(t, err)
+		assert.Equal(t, name, proof.Metadata["algorithm"])
+
+		// Verification reads the algorithm from the signature value
+		assert.NoError(t, NewProofGenerator().VerifySignature(data, proof.Signature, key.PublicKey()), name)
+
+		tampered := map[string]string{"subject": "other"}
+		assert.Error(t, pg.VerifySignature(tampered, proof.Signature, key.PublicKey()), name)
+	}
+
+	// Signatures over bare digests, without the multihash, are rejected
+	canonical, err := NewProofGenerator().Canonicalize(data)
+	assert.NoError(t, err)
+	digest := sha3.Sum256(canonical)
+	signature, err := key.Sign(digest[:])
+	assert.NoError(t, err)
+	legacy := base64.StdEncoding.EncodeToString(signature)
+	assert.Error(t, NewProofGenerator().VerifySignature(data, legacy, key.PublicKey()))
+}
+
+func TestCID(t *testing.T) {
+	data := []byte("axia")
+	cid := NewCID(data)
+
+	parsed, err := ParseCID(cid.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cid.String(), parsed.String())
+	assert.NoError(t, parsed.Verify(data))
+	assert.ErrorIs(t, parsed.Verify([]byte("other")), ErrCIDMismatch)
+	assert.True(t, bytes.HasPrefix([]byte(cid.String()), []byte("bafkrei")))
+}
@@ -0,0 +1,123 @@
+package crypto
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+// Multibase prefixes understood by EncodeMultibase and DecodeMultibase
+const (
+	MultibaseBase58BTC = 'z'
+	MultibaseBase64URL = 'u'
+	MultibaseBase32    = 'b'
+)
+
+// multibaseBase32 is lower-case RFC 4648 base32 without padding
+var multibaseBase32 = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+var ErrInvalidMultibase = errors.New("invalid multibase string")
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Index = func() [256]int {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		index[base58Alphabet[i]] = i
+	}
+	return index
+}()
+
+// EncodeMultibase encodes data as a base58btc multibase string
+func EncodeMultibase(data []byte) string {
+	return string(MultibaseBase58BTC) + EncodeBase58(data)
+}
+
+// EncodeMultibaseBase64URL encodes data as an unpadded base64url multibase
+// string
+func EncodeMultibaseBase64URL(data []byte) string {
+	return string(MultibaseBase64URL) + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// EncodeMultibaseBase32 encodes data as a lower-case base32 multibase
+// string, the encoding of CIDv1
+func EncodeMultibaseBase32(data []byte) string {
+	return string(MultibaseBase32) + multibaseBase32.EncodeToString(data)
+}
+
+// DecodeMultibase decodes a multibase string
+func DecodeMultibase(s string) ([]byte, error) {
+	if len(s) < 1 {
+		return nil, ErrInvalidMultibase
+	}
+
+	switch s[0] {
+	case MultibaseBase58BTC:
+		return DecodeBase58(s[1:])
+	case MultibaseBase32:
+		data, err := multibaseBase32.DecodeString(s[1:])
+		if err != nil {
+			return nil, ErrInvalidMultibase
+		}
+		return data, nil
+	case MultibaseBase64URL:
+		data, err := base64.RawURLEncoding.DecodeString(s[1:])
+		if err != nil {
+			return nil, ErrInvalidMultibase
+		}
+		return data, nil
+	default:
+		return nil, ErrInvalidMultibase
+	}
+}
+
+// EncodeBase58 encodes data with the Bitcoin base58 alphabet
+func EncodeBase58(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// DecodeBase58 decodes a Bitcoin base58 string
+func DecodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := zeros; i < len(s); i++ {
+		digit := base58Index[s[i]]
+		if digit < 0 {
+			return nil, ErrInvalidMultibase
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
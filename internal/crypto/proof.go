@@ -1,46 +1,196 @@
 package crypto
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
-	"golang.org/x/crypto/sha3"
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/canon"
+)
+
+// Canonicalization algorithms applied to data before hashing
+const (
+	CanonicalizationJCS = "jcs"
+	CanonicalizationRDF = "urdna2015"
 )
 
+var ErrUnsupportedCanonicalization = errors.New("unsupported canonicalization algorithm")
+
 // Proof represents a cryptographic proof for a graph element
 type Proof struct {
 	Hash      string            `json:"hash"`
+	Signature string            `json:"signature,omitempty"`
 	Timestamp int64             `json:"timestamp"`
 	Metadata  map[string]string `json:"metadata"`
 }
 
-// ProofGenerator handles creation of cryptographic proofs
+// ProofGenerator handles creation of cryptographic proofs. Digests are
+// multihashes, so proofs name the hash algorithm that produced them.
 type ProofGenerator struct {
-	algorithm string
+	algorithm        *HashAlgorithm
+	canonicalization string
 }
 
-// NewProofGenerator creates a new proof generator
+// NewProofGenerator creates a new proof generator using JCS canonicalization
+// and the default hash algorithm
 func NewProofGenerator() *ProofGenerator {
+	hashMu.RLock()
+	algorithm := hashAlgorithms[defaultHash]
+	hashMu.RUnlock()
+
 	return &ProofGenerator{
-		algorithm: "sha3-256",
+		algorithm:        algorithm,
+		canonicalization: CanonicalizationJCS,
+	}
+}
+
+// SetHashAlgorithm selects the hash algorithm of new proofs
+func (pg *ProofGenerator) SetHashAlgorithm(name string) error {
+	algorithm, err := LookupHash(name)
+	if err != nil {
+		return err
+	}
+	pg.algorithm = algorithm
+	return nil
+}
+
+// HashAlgorithm returns the name of the hash algorithm in use
+func (pg *ProofGenerator) HashAlgorithm() string {
+	return pg.algorithm.Name
+}
+
+// SetCanonicalization selects how data is canonicalized before hashing.
+// An empty name selects JCS.
+func (pg *ProofGenerator) SetCanonicalization(name string) error {
+	switch name {
+	case "":
+		pg.canonicalization = CanonicalizationJCS
+	case CanonicalizationJCS, CanonicalizationRDF:
+		pg.canonicalization = name
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedCanonicalization, name)
 	}
+	return nil
+}
+
+// Canonicalization returns the canonicalization algorithm in use
+func (pg *ProofGenerator) Canonicalization() string {
+	return pg.canonicalization
 }
 
-// GenerateProof creates a cryptographic proof for any data
+// GenerateProof creates a cryptographic proof for any data. Its hash is
+// the multibase-encoded multihash of the canonical data.
 func (pg *ProofGenerator) GenerateProof(data interface{}) (*Proof, error) {
-	jsonData, err := json.Marshal(data)
+	digest, err := pg.digest(pg.algorithm, data)
 	if err != nil {
 		return nil, err
 	}
 
-	hash := sha3.New256()
-	hash.Write(jsonData)
-	
 	return &Proof{
-		Hash:      hex.EncodeToString(hash.Sum(nil)),
+		Hash:      EncodeMultibase(digest),
 		Timestamp: time.Now().Unix(),
 		Metadata: map[string]string{
-			"algorithm": pg.algorithm,
-			"type":     "sha3-256",
+			"algorithm":        pg.algorithm.Name,
+			"canonicalization": pg.canonicalization,
 		},
 	}, nil
-} 
\ No newline at end of file
+}
+
+// SignProof creates a proof whose digest is signed by the given signer.
+// The signature covers the multihash, and the proof's signature value is
+// the multibase encoding of the multihash followed by the signature.
+func (pg *ProofGenerator) SignProof(data interface{}, signer Signer) (*Proof, error) {
+	digest, err := pg.digest(pg.algorithm, data)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proof{
+		Hash:      EncodeMultibase(digest),
+		Signature: EncodeMultibase(append(digest, signature...)),
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"algorithm":        pg.algorithm.Name,
+			"type":             "Ed25519Signature",
+			"canonicalization": pg.canonicalization,
+			"publicKey":        hex.EncodeToString(signer.PublicKey()),
+		},
+	}, nil
+}
+
+// VerifySignature recomputes the digest of data with the hash algorithm
+// named in the signature value and checks the signature against it.
+// Signatures must be multibase values starting with the multihash of the
+// signed digest.
+func (pg *ProofGenerator) VerifySignature(data interface{}, signature string, publicKey ed25519.PublicKey) error {
+	value, err := DecodeMultibase(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mh, sig, err := splitMultihash(value)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	algorithm, _, err := DecodeMultihash(mh)
+	if err != nil {
+		return err
+	}
+
+	digest, err := pg.digest(algorithm, data)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest, mh) {
+		return ErrInvalidSignature
+	}
+	return Verify(publicKey, digest, sig)
+}
+
+// splitMultihash splits data into the multihash it starts with and the
+// remaining bytes
+func splitMultihash(data []byte) (mh, rest []byte, err error) {
+	_, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, ErrInvalidMultihash
+	}
+	length, m := binary.Uvarint(data[n:])
+	if m <= 0 || uint64(len(data)-n-m) < length {
+		return nil, nil, ErrInvalidMultihash
+	}
+	end := n + m + int(length)
+	return data[:end], data[end:], nil
+}
+
+// Canonicalize serializes data in the generator's canonical form
+func (pg *ProofGenerator) Canonicalize(data interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pg.canonicalization {
+	case CanonicalizationRDF:
+		return canon.RDF(jsonData)
+	default:
+		return canon.JCS(jsonData)
+	}
+}
+
+// digest returns the multihash of the canonical form of data
+func (pg *ProofGenerator) digest(algorithm *HashAlgorithm, data interface{}) ([]byte, error) {
+	canonical, err := pg.Canonicalize(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize data: %w", err)
+	}
+	return algorithm.Sum(canonical), nil
+}
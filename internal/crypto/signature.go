@@ -0,0 +1,77 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	ErrInvalidSeed      = errors.New("invalid private key seed")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signer produces signatures on behalf of an agent
+type Signer interface {
+	PublicKey() ed25519.PublicKey
+	Sign(message []byte) ([]byte, error)
+}
+
+// KeyPair is an in-memory Ed25519 signing key
+type KeyPair struct {
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+// GenerateKeyPair creates a new random Ed25519 key pair
+func GenerateKeyPair() (*KeyPair, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	return &KeyPair{public: public, private: private}, nil
+}
+
+// NewKeyPairFromSeed restores a key pair from its 32-byte seed
+func NewKeyPairFromSeed(seed []byte) (*KeyPair, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidSeed
+	}
+
+	private := ed25519.NewKeyFromSeed(seed)
+	return &KeyPair{
+		public:  private.Public().(ed25519.PublicKey),
+		private: private,
+	}, nil
+}
+
+// PublicKey returns the verification key of the pair
+func (k *KeyPair) PublicKey() ed25519.PublicKey {
+	return k.public
+}
+
+// Seed returns the private seed the pair was derived from
+func (k *KeyPair) Seed() []byte {
+	return k.private.Seed()
+}
+
+// Sign signs the message with the private key
+func (k *KeyPair) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(k.private, message), nil
+}
+
+// Verify checks an Ed25519 signature over the message
+func Verify(publicKey ed25519.PublicKey, message, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidPublicKey
+	}
+
+	if !ed25519.Verify(publicKey, message, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
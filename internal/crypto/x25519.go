@@ -0,0 +1,60 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// fieldPrime is the prime 2^255 - 19 of the field both curves are defined
+// over
+var fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// X25519PublicKey converts an Ed25519 public key to the X25519 public key
+// of the same key pair, so Ed25519 identities can receive encrypted data.
+// The Montgomery u-coordinate is (1 + y) / (1 - y) (RFC 7748, section 4.1).
+func X25519PublicKey(publicKey ed25519.PublicKey) ([]byte, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// y is encoded little-endian, with the sign of x in the top bit
+	encoded := make([]byte, ed25519.PublicKeySize)
+	for i, b := range publicKey {
+		encoded[len(encoded)-1-i] = b
+	}
+	encoded[0] &= 0x7f
+	y := new(big.Int).SetBytes(encoded)
+	if y.Cmp(fieldPrime) >= 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, fieldPrime)
+	if denominator.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, denominator.ModInverse(denominator, fieldPrime))
+	u.Mod(u, fieldPrime)
+
+	out := make([]byte, curve25519.PointSize)
+	u.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
+
+// X25519PrivateKey returns the X25519 private key of the pair: the scalar
+// Ed25519 derives from the seed
+func (k *KeyPair) X25519PrivateKey() []byte {
+	digest := sha512.Sum512(k.Seed())
+	scalar := digest[:curve25519.ScalarSize]
+	scalar[0] &= 248
+	scalar[31] &= 127
+	scalar[31] |= 64
+	return scalar
+}
@@ -0,0 +1,20 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/curve25519"
+)
+
+func TestX25519PublicKey(t *testing.T) {
+	key, err := GenerateKeyPair()
+	assert.NoError(t, err)
+
+	// The converted public key matches the private key Ed25519 derives
+	converted, err := X25519PublicKey(key.PublicKey())
+	assert.NoError(t, err)
+	derived, err := curve25519.X25519(key.X25519PrivateKey(), curve25519.Basepoint)
+	assert.NoError(t, err)
+	assert.Equal(t, derived, converted)
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"axia/internal/axiom"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// ChainHead returns the sequence and hash of the latest claim issued for
+// agent by this node. It implements axiom.ChainStore.
+func (db *DB) ChainHead(ctx context.Context, agent string) (uint64, string, error) {
+	var sequence uint64
+	var hash string
+	err := db.pool.QueryRow(ctx,
+		`SELECT sequence, hash FROM chain_heads WHERE issuer = $1`, agent).Scan(&sequence, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get chain head: %w", err)
+	}
+	return sequence, hash, nil
+}
+
+// SetChainHead records the latest claim issued for agent. Heads only move
+// forward.
+func (db *DB) SetChainHead(ctx context.Context, agent string, sequence uint64, hash string) error {
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO chain_heads (issuer, sequence, hash)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (issuer) DO UPDATE
+		 SET sequence = EXCLUDED.sequence, hash = EXCLUDED.hash, updated_at = CURRENT_TIMESTAMP
+		 WHERE chain_heads.sequence < EXCLUDED.sequence`,
+		agent, sequence, hash)
+	if err != nil {
+		return fmt.Errorf("failed to set chain head: %w", err)
+	}
+	return nil
+}
+
+// checkChain compares a claim being stored with its stored neighbours in
+// the issuer's chain and records any duplicate sequence or fork
+func (db *DB) checkChain(ctx context.Context, tx pgx.Tx, claim *axiom.Claim) error {
+	if claim.Sequence == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT document FROM claims
+		 WHERE issuer = $1 AND sequence BETWEEN $2 AND $3`,
+		claim.Issuer, claim.Sequence-1, claim.Sequence+1)
+	if err != nil {
+		return fmt.Errorf("failed to query claim chain: %w", err)
+	}
+	known, err := scanClaims(rows)
+	if err != nil {
+		return err
+	}
+
+	for _, equivocation := range axiom.CheckChain(claim, known) {
+		evidence, err := json.Marshal(equivocation)
+		if err != nil {
+			return fmt.Errorf("failed to encode equivocation: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO chain_equivocations (issuer, sequence, kind, first_uri, second_uri, evidence)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 ON CONFLICT (kind, first_uri, second_uri) DO NOTHING`,
+			equivocation.Issuer,
+			equivocation.Sequence,
+			equivocation.Kind,
+			axiom.ClaimID(equivocation.Claims[0]),
+			axiom.ClaimID(equivocation.Claims[1]),
+			evidence,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert equivocation: %w", err)
+		}
+
+		db.logger.WithFields(logrus.Fields{
+			"issuer":   equivocation.Issuer,
+			"kind":     equivocation.Kind,
+			"sequence": equivocation.Sequence,
+		}).Warn("Issuer equivocated")
+	}
+	return nil
+}
+
+// Equivocations returns the recorded duplicate sequences and forks,
+// followed by the gaps in the stored claim chains
+func (db *DB) Equivocations(ctx context.Context) ([]*axiom.Equivocation, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT evidence FROM chain_equivocations ORDER BY detected_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equivocations: %w", err)
+	}
+	defer rows.Close()
+
+	var found []*axiom.Equivocation
+	for rows.Next() {
+		var evidence []byte
+		if err := rows.Scan(&evidence); err != nil {
+			return nil, fmt.Errorf("failed to scan equivocation: %w", err)
+		}
+
+		equivocation := &axiom.Equivocation{}
+		if err := json.Unmarshal(evidence, equivocation); err != nil {
+			return nil, fmt.Errorf("failed to decode equivocation: %w", err)
+		}
+		found = append(found, equivocation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A claim follows a gap when its issuer has no claim just before it
+	rows, err = db.pool.Query(ctx,
+		`SELECT c.document FROM claims c
+		 WHERE c.sequence > 1 AND NOT EXISTS (
+		     SELECT 1 FROM claims p
+		     WHERE p.issuer = c.issuer AND p.sequence = c.sequence - 1)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chain gaps: %w", err)
+	}
+	following, err := scanClaims(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, claim := range following {
+		var latest uint64
+		err := db.pool.QueryRow(ctx,
+			`SELECT COALESCE(MAX(sequence), 0) FROM claims
+			 WHERE issuer = $1 AND sequence < $2`,
+			claim.Issuer, claim.Sequence).Scan(&latest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query claim chain: %w", err)
+		}
+
+		found = append(found, &axiom.Equivocation{
+			Kind:     axiom.EquivocationGap,
+			Issuer:   claim.Issuer,
+			Sequence: latest + 1,
+			Claims:   []*axiom.Claim{claim},
+		})
+	}
+	return found, nil
+}
+
+// scanClaims decodes and closes rows of claim documents
+func scanClaims(rows pgx.Rows) ([]*axiom.Claim, error) {
+	defer rows.Close()
+
+	var claims []*axiom.Claim
+	for rows.Next() {
+		var document []byte
+		if err := rows.Scan(&document); err != nil {
+			return nil, fmt.Errorf("failed to scan claim: %w", err)
+		}
+		claim := &axiom.Claim{}
+		if err := json.Unmarshal(document, claim); err != nil {
+			return nil, fmt.Errorf("failed to decode claim: %w", err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
@@ -2,25 +2,71 @@ package database
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"axia/internal/axiom"
+	"axia/internal/did"
 )
 
-// StoreClaim stores a new claim in the database
+var (
+	ErrClaimNotFound = errors.New("claim not found")
+	ErrClaimConflict = errors.New("another claim is stored under this ID")
+)
+
+// SetResolver sets the resolver that binds the issuers and co-signers of
+// stored claims to their keys, such as axiom.Manager.Resolver
+func (db *DB) SetResolver(resolver did.Resolver) {
+	db.resolver = resolver
+}
+
+// StoreClaim stores a new claim in the database. Claims must be signed by
+// keys the resolver binds to their issuers and co-signers. Storing the
+// same signed claim again with more co-signatures updates the stored
+// document; any other claim under a stored claim's ID is rejected with
+// ErrClaimConflict.
 func (db *DB) StoreClaim(ctx context.Context, claim *axiom.Claim) error {
+	if err := axiom.VerifyClaim(ctx, db.resolver, claim); err != nil {
+		return fmt.Errorf("refusing to store claim: %w", err)
+	}
+	if !axiom.ThresholdMet(claim) {
+		return fmt.Errorf("refusing to store claim: %w", axiom.ErrThresholdNotMet)
+	}
+
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	document, err := json.Marshal(claim)
+	if err != nil {
+		return fmt.Errorf("failed to encode claim: %w", err)
+	}
+
 	var claimID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM claims WHERE uri = $1`, axiom.ClaimID(claim)).Scan(&claimID)
+	if err == nil {
+		return db.addCoSignatures(ctx, tx, claimID, claim)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to look up claim: %w", err)
+	}
+
+	// Claims contradicting their issuer's history are stored, since they
+	// are validly signed, and recorded as evidence against the issuer
+	if err := db.checkChain(ctx, tx, claim); err != nil {
+		return err
+	}
+
 	err = tx.QueryRow(ctx,
-		`INSERT INTO claims (issuer, subject, axiom_text, confidence, proof_type, proof_value, proof_created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`INSERT INTO claims (uri, issuer, subject, axiom_text, confidence, proof_type, proof_value, proof_created_at, sequence, previous, document)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		 RETURNING id`,
+		axiom.ClaimID(claim),
 		claim.Issuer,
 		claim.ClaimBody.Subject,
 		claim.ClaimBody.Rating.Axiom,
@@ -28,6 +74,9 @@ func (db *DB) StoreClaim(ctx context.Context, claim *axiom.Claim) error {
 		claim.Proof.Type,
 		claim.Proof.ProofValue,
 		claim.Proof.Created,
+		claim.Sequence,
+		claim.Previous,
+		document,
 	).Scan(&claimID)
 	
 	if err != nil {
@@ -44,22 +93,108 @@ func (db *DB) StoreClaim(ctx context.Context, claim *axiom.Claim) error {
 		}
 	}
 
-	return tx.Commit(ctx)
+	return db.commitClaim(ctx, tx, claim)
 }
 
-// QueryClaims retrieves claims based on filters
+// addCoSignatures replaces the stored claim with claim if it is the same
+// signed claim with more co-signatures. Claim IDs are chosen by issuers, so
+// a claim reusing another's ID must not replace it.
+func (db *DB) addCoSignatures(ctx context.Context, tx pgx.Tx, claimID uuid.UUID, claim *axiom.Claim) error {
+	var document []byte
+	err := tx.QueryRow(ctx, `SELECT document FROM claims WHERE id = $1 FOR UPDATE`, claimID).Scan(&document)
+	if err != nil {
+		return fmt.Errorf("failed to get stored claim: %w", err)
+	}
+	stored := &axiom.Claim{}
+	if err := json.Unmarshal(document, stored); err != nil {
+		return fmt.Errorf("failed to decode stored claim: %w", err)
+	}
+	if !axiom.SameClaim(stored, claim) {
+		return fmt.Errorf("%w: %s", ErrClaimConflict, axiom.ClaimID(claim))
+	}
+
+	// Every stored co-signature must be kept
+	signers := make(map[string]bool)
+	for _, signer := range claim.Signers() {
+		signers[signer] = true
+	}
+	for _, signer := range stored.Signers() {
+		if !signers[signer] {
+			return fmt.Errorf("%w: %s drops the co-signature of %s", ErrClaimConflict, axiom.ClaimID(claim), signer)
+		}
+	}
+	if len(claim.Signers()) == len(stored.Signers()) {
+		return fmt.Errorf("%w: %s adds no co-signatures", ErrClaimConflict, axiom.ClaimID(claim))
+	}
+
+	// The stored claim's timestamp token predates any later one
+	updated := *claim
+	if stored.Proof.Timestamp != nil {
+		updated.Proof.Timestamp = stored.Proof.Timestamp
+	}
+	document, err = json.Marshal(&updated)
+	if err != nil {
+		return fmt.Errorf("failed to encode claim: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE claims SET document = $2 WHERE id = $1`, claimID, document); err != nil {
+		return fmt.Errorf("failed to update claim: %w", err)
+	}
+	return db.commitClaim(ctx, tx, &updated)
+}
+
+// commitClaim commits a transaction storing claim and records the claim in
+// the transparency log
+func (db *DB) commitClaim(ctx context.Context, tx pgx.Tx, claim *axiom.Claim) error {
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if db.log != nil {
+		if _, err := db.log.AppendClaim(ctx, claim); err != nil {
+			return fmt.Errorf("claim stored but not logged: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetClaim returns the claim with the given URI, as computed by
+// axiom.ClaimID
+func (db *DB) GetClaim(ctx context.Context, uri string) (*axiom.Claim, error) {
+	var document []byte
+	err := db.pool.QueryRow(ctx,
+		`SELECT document FROM claims WHERE uri = $1`, uri).Scan(&document)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrClaimNotFound, uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+
+	claim := &axiom.Claim{}
+	if err := json.Unmarshal(document, claim); err != nil {
+		return nil, fmt.Errorf("failed to decode claim: %w", err)
+	}
+	return claim, nil
+}
+
+// QueryClaims retrieves claims based on filters. Revoked claims are left
+// out unless the include_revoked filter is set.
 func (db *DB) QueryClaims(ctx context.Context, filters map[string]interface{}) ([]*axiom.Claim, error) {
 	query := `
-		SELECT DISTINCT c.id, c.issuer, c.subject, c.axiom_text, c.confidence,
-		       c.proof_type, c.proof_value, c.proof_created_at
+		SELECT DISTINCT c.document
 		FROM claims c
 		LEFT JOIN claim_tags ct ON c.id = ct.claim_id
+		LEFT JOIN claim_revocations cr ON c.uri = cr.claim_uri
 		WHERE 1=1
 	`
 	
 	args := make([]interface{}, 0)
 	argPos := 1
 
+	if _, ok := filters["include_revoked"]; !ok {
+		query += " AND cr.claim_uri IS NULL"
+	}
+
 	if v, ok := filters["issuer"]; ok {
 		query += fmt.Sprintf(" AND c.issuer = $%d", argPos)
 		args = append(args, v)
@@ -82,26 +217,5 @@ func (db *DB) QueryClaims(ctx context.Context, filters map[string]interface{}) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to query claims: %w", err)
 	}
-	defer rows.Close()
-
-	var claims []*axiom.Claim
-	for rows.Next() {
-		claim := &axiom.Claim{}
-		err := rows.Scan(
-			&claim.ID,
-			&claim.Issuer,
-			&claim.ClaimBody.Subject,
-			&claim.ClaimBody.Rating.Axiom,
-			&claim.ClaimBody.Rating.ConfidenceValue,
-			&claim.Proof.Type,
-			&claim.Proof.ProofValue,
-			&claim.Proof.Created,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan claim: %w", err)
-		}
-		claims = append(claims, claim)
-	}
-
-	return claims, nil
-} 
\ No newline at end of file
+	return scanClaims(rows)
+}
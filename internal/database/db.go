@@ -9,12 +9,18 @@ import (
 	"github.com/sirupsen/logrus"
 	"axia/internal/axiom"
 	"axia/internal/auth"
+	"axia/internal/did"
+	"axia/internal/translog"
 )
 
 type DB struct {
 	pool   *pgxpool.Pool
 	logger *logrus.Logger
 	auth   *auth.Authenticator
+	log    *translog.Log
+	// resolver binds the issuers and co-signers of stored claims to
+	// their keys
+	resolver did.Resolver
 }
 
 type Config struct {
@@ -35,8 +41,9 @@ func New(cfg Config, logger *logrus.Logger) (*DB, error) {
 	}
 	
 	return &DB{
-		pool:   pool,
-		logger: logger,
+		pool:     pool,
+		logger:   logger,
+		resolver: did.NewRegistry(),
 	}, nil
 }
 
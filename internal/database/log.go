@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"axia/internal/translog"
+	"github.com/jackc/pgx/v4"
+)
+
+// logStorage keeps the transparency log in the log_entries table
+type logStorage struct {
+	db *DB
+}
+
+// LogStorage returns the database-backed transparency log storage
+func (db *DB) LogStorage() translog.Storage {
+	return &logStorage{db: db}
+}
+
+// SetLog sets the transparency log that stored claims are appended to
+func (db *DB) SetLog(log *translog.Log) {
+	db.log = log
+}
+
+// Append inserts a log entry at the next index, serializing concurrent
+// writers with a table lock
+func (s *logStorage) Append(ctx context.Context, leafHash, data []byte) (uint64, error) {
+	tx, err := s.db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE log_entries IN EXCLUSIVE MODE`); err != nil {
+		return 0, fmt.Errorf("failed to lock log: %w", err)
+	}
+
+	var index int64
+	err = tx.QueryRow(ctx,
+		`SELECT idx FROM log_entries WHERE leaf_hash = $1`, leafHash).Scan(&index)
+	if err == nil {
+		return uint64(index), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to query log entry: %w", err)
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO log_entries (idx, leaf_hash, leaf_data)
+		 SELECT COALESCE(MAX(idx) + 1, 0), $1, $2 FROM log_entries
+		 RETURNING idx`,
+		leafHash, data,
+	).Scan(&index)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert log entry: %w", err)
+	}
+
+	return uint64(index), tx.Commit(ctx)
+}
+
+// LeafHashes returns all leaf hashes in log order
+func (s *logStorage) LeafHashes(ctx context.Context) ([][]byte, error) {
+	rows, err := s.db.pool.Query(ctx, `SELECT leaf_hash FROM log_entries ORDER BY idx`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query log entries: %w", err)
+	}
+	defer rows.Close()
+
+	var leaves [][]byte
+	for rows.Next() {
+		var leaf []byte
+		if err := rows.Scan(&leaf); err != nil {
+			return nil, fmt.Errorf("failed to scan log entry: %w", err)
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, rows.Err()
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"axia/internal/axiom"
+)
+
+// RevocationEntry is a stored revocation with its status list index
+type RevocationEntry struct {
+	Revocation  *axiom.Revocation
+	StatusIndex uint64
+}
+
+// StoreRevocation stores the revocation of a stored claim. Callers verify
+// the revocation against the claim with axiom.VerifyRevocation first.
+func (db *DB) StoreRevocation(ctx context.Context, revocation *axiom.Revocation, statusIndex uint64) error {
+	if err := axiom.VerifyRevocationProof(revocation); err != nil {
+		return fmt.Errorf("refusing to store revocation: %w", err)
+	}
+
+	document, err := json.Marshal(revocation)
+	if err != nil {
+		return fmt.Errorf("failed to encode revocation: %w", err)
+	}
+
+	_, err = db.pool.Exec(ctx,
+		`INSERT INTO claim_revocations (claim_uri, status_index, document, revoked_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (claim_uri) DO NOTHING`,
+		revocation.ClaimID,
+		int64(statusIndex),
+		document,
+		revocation.Revoked,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert revocation: %w", err)
+	}
+
+	if db.log != nil {
+		if _, err := db.log.AppendRevocation(ctx, revocation); err != nil {
+			return fmt.Errorf("revocation stored but not logged: %w", err)
+		}
+	}
+	return nil
+}
+
+// Revocations returns all stored revocations
+func (db *DB) Revocations(ctx context.Context) ([]*RevocationEntry, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT document, status_index FROM claim_revocations ORDER BY status_index`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revocations: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*RevocationEntry
+	for rows.Next() {
+		var document []byte
+		var statusIndex int64
+		if err := rows.Scan(&document, &statusIndex); err != nil {
+			return nil, fmt.Errorf("failed to scan revocation: %w", err)
+		}
+
+		revocation := &axiom.Revocation{}
+		if err := json.Unmarshal(document, revocation); err != nil {
+			return nil, fmt.Errorf("failed to decode revocation: %w", err)
+		}
+		entries = append(entries, &RevocationEntry{
+			Revocation:  revocation,
+			StatusIndex: uint64(statusIndex),
+		})
+	}
+	return entries, rows.Err()
+}
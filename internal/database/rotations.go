@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"axia/internal/delegation"
+)
+
+// StoreRotation stores a key rotation. Callers add it to the manager's key
+// history first, which checks its signature and order.
+func (db *DB) StoreRotation(ctx context.Context, rotation *delegation.Rotation) error {
+	document, err := json.Marshal(rotation)
+	if err != nil {
+		return fmt.Errorf("failed to encode key rotation: %w", err)
+	}
+
+	_, err = db.pool.Exec(ctx,
+		`INSERT INTO key_rotations (issuer, previous_key, next_key, effective_at, document)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (issuer, previous_key) DO NOTHING`,
+		rotation.Issuer,
+		rotation.Previous,
+		rotation.Next,
+		rotation.Effective,
+		document,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert key rotation: %w", err)
+	}
+	return nil
+}
+
+// Rotations returns all stored key rotations in the order they took effect
+func (db *DB) Rotations(ctx context.Context) ([]*delegation.Rotation, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT document FROM key_rotations ORDER BY issuer, effective_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query key rotations: %w", err)
+	}
+	defer rows.Close()
+
+	var rotations []*delegation.Rotation
+	for rows.Next() {
+		var document []byte
+		if err := rows.Scan(&document); err != nil {
+			return nil, fmt.Errorf("failed to scan key rotation: %w", err)
+		}
+
+		rotation := &delegation.Rotation{}
+		if err := json.Unmarshal(document, rotation); err != nil {
+			return nil, fmt.Errorf("failed to decode key rotation: %w", err)
+		}
+		rotations = append(rotations, rotation)
+	}
+	return rotations, rows.Err()
+}
@@ -1,8 +1,9 @@
 -- Schema for Axia Trust Graph Database
 
 -- Claims table stores all axiomatic claims
-CREATE TABLE claims (
+CREATE TABLE IF NOT EXISTS claims (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    uri TEXT NOT NULL UNIQUE,
     issuer VARCHAR(255) NOT NULL,
     subject VARCHAR(255) NOT NULL,
     axiom_text TEXT NOT NULL,
@@ -10,19 +11,59 @@ CREATE TABLE claims (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     proof_type VARCHAR(100) NOT NULL,
     proof_value TEXT NOT NULL,
-    proof_created_at TIMESTAMP WITH TIME ZONE NOT NULL
+    proof_created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sequence BIGINT NOT NULL DEFAULT 0,
+    previous TEXT NOT NULL DEFAULT '',
+    document JSONB NOT NULL
 );
 
 -- Tags for claims
-CREATE TABLE claim_tags (
+CREATE TABLE IF NOT EXISTS claim_tags (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     claim_id UUID NOT NULL REFERENCES claims(id) ON DELETE CASCADE,
     tag VARCHAR(100) NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Databases created before claims kept their URI, chain position and signed
+-- document gain those columns here. Old rows get the URI axiom.ClaimID
+-- computes for claims without an ID, suffixed if the same claim was stored
+-- twice, and a document rebuilt from their columns; it lacks the verifier,
+-- so old claims stay queryable but no longer verify.
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS uri TEXT;
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS sequence BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS previous TEXT NOT NULL DEFAULT '';
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS document JSONB;
+
+UPDATE claims SET uri = 'urn:axia:proof:' || encode(sha256(convert_to(claims.proof_value, 'UTF8')), 'hex') ||
+    CASE WHEN ranked.n > 1 THEN '#duplicate-' || ranked.n ELSE '' END
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY proof_value ORDER BY created_at, id) AS n
+    FROM claims WHERE uri IS NULL
+) ranked
+WHERE claims.id = ranked.id;
+
+UPDATE claims SET document = jsonb_build_object(
+    '@context', 'https://schema.axios.ai/AxiomaticClaim.jsonld',
+    'type', 'AxiomaticClaim',
+    'issuer', issuer,
+    'issued', proof_created_at,
+    'claim', jsonb_build_object(
+        'subject', subject,
+        'agent', issuer,
+        'tags', COALESCE((SELECT jsonb_agg(tag) FROM claim_tags WHERE claim_tags.claim_id = claims.id), '[]'::jsonb),
+        'axiomRating', jsonb_build_object('axiom', axiom_text, 'confidenceValue', confidence)
+    ),
+    'proof', jsonb_build_object('type', proof_type, 'created', proof_created_at, 'proofValue', proof_value)
+)
+WHERE document IS NULL;
+
+ALTER TABLE claims ALTER COLUMN uri SET NOT NULL;
+ALTER TABLE claims ALTER COLUMN document SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS claims_uri_key ON claims(uri);
+
 -- Trust graph edges
-CREATE TABLE trust_edges (
+CREATE TABLE IF NOT EXISTS trust_edges (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     from_node UUID NOT NULL REFERENCES claims(id),
     to_node UUID NOT NULL REFERENCES claims(id),
@@ -31,7 +72,7 @@ CREATE TABLE trust_edges (
 );
 
 -- Twitter reports
-CREATE TABLE twitter_reports (
+CREATE TABLE IF NOT EXISTS twitter_reports (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     tweet_id VARCHAR(255) NOT NULL UNIQUE,
     author_id VARCHAR(255) NOT NULL,
@@ -44,7 +85,7 @@ CREATE TABLE twitter_reports (
 );
 
 -- IPFS records table
-CREATE TABLE ipfs_records (
+CREATE TABLE IF NOT EXISTS ipfs_records (
     id UUID PRIMARY KEY,
     ipfs_id VARCHAR(255) NOT NULL,
     type VARCHAR(100) NOT NULL,
@@ -52,13 +93,72 @@ CREATE TABLE ipfs_records (
     created_at TIMESTAMP WITH TIME ZONE NOT NULL
 );
 
+-- Transparency log entries, in append order
+CREATE TABLE IF NOT EXISTS log_entries (
+    idx BIGINT PRIMARY KEY,
+    leaf_hash BYTEA NOT NULL UNIQUE,
+    leaf_data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tokens issued by the node's timestamp authority; each token names the
+-- hash of the one before it
+CREATE TABLE IF NOT EXISTS timestamp_tokens (
+    serial BIGINT PRIMARY KEY,
+    imprint TEXT NOT NULL,
+    gen_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    token JSONB NOT NULL
+);
+
+-- Claim revocations; status_index is the claim's entry in the published
+-- revocation status list
+CREATE TABLE IF NOT EXISTS claim_revocations (
+    claim_uri TEXT PRIMARY KEY REFERENCES claims(uri) ON DELETE CASCADE,
+    status_index BIGINT NOT NULL,
+    document JSONB NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Agent key rotations; each one is signed by the key it replaces
+CREATE TABLE IF NOT EXISTS key_rotations (
+    issuer VARCHAR(255) NOT NULL,
+    previous_key TEXT NOT NULL,
+    next_key TEXT NOT NULL,
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    document JSONB NOT NULL,
+    PRIMARY KEY (issuer, previous_key)
+);
+
+-- Latest claim of each agent's claim chain issued by this node
+CREATE TABLE IF NOT EXISTS chain_heads (
+    issuer VARCHAR(255) PRIMARY KEY,
+    sequence BIGINT NOT NULL,
+    hash TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Duplicate sequences and forks found in issuers' claim chains; evidence
+-- holds both conflicting claims
+CREATE TABLE IF NOT EXISTS chain_equivocations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    issuer VARCHAR(255) NOT NULL,
+    sequence BIGINT NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    first_uri TEXT NOT NULL,
+    second_uri TEXT NOT NULL,
+    evidence JSONB NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, first_uri, second_uri)
+);
+
 -- Create indexes
-CREATE INDEX idx_claims_issuer ON claims(issuer);
-CREATE INDEX idx_claims_subject ON claims(subject);
-CREATE INDEX idx_claim_tags_claim_id ON claim_tags(claim_id);
-CREATE INDEX idx_claim_tags_tag ON claim_tags(tag);
-CREATE INDEX idx_trust_edges_from_node ON trust_edges(from_node);
-CREATE INDEX idx_trust_edges_to_node ON trust_edges(to_node);
-CREATE INDEX idx_twitter_reports_tweet_id ON twitter_reports(tweet_id);
-CREATE INDEX idx_ipfs_records_type ON ipfs_records(type);
-CREATE INDEX idx_ipfs_records_created_at ON ipfs_records(created_at); 
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_claims_issuer ON claims(issuer);
+CREATE INDEX IF NOT EXISTS idx_claims_subject ON claims(subject);
+CREATE INDEX IF NOT EXISTS idx_claims_issuer_sequence ON claims(issuer, sequence);
+CREATE INDEX IF NOT EXISTS idx_claim_tags_claim_id ON claim_tags(claim_id);
+CREATE INDEX IF NOT EXISTS idx_claim_tags_tag ON claim_tags(tag);
+CREATE INDEX IF NOT EXISTS idx_trust_edges_from_node ON trust_edges(from_node);
+CREATE INDEX IF NOT EXISTS idx_trust_edges_to_node ON trust_edges(to_node);
+CREATE INDEX IF NOT EXISTS idx_twitter_reports_tweet_id ON twitter_reports(tweet_id);
+CREATE INDEX IF NOT EXISTS idx_ipfs_records_type ON ipfs_records(type);
+CREATE INDEX IF NOT EXISTS idx_ipfs_records_created_at ON ipfs_records(created_at); 
\ No newline at end of file
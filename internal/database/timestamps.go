@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"axia/internal/timestamp"
+	"github.com/jackc/pgx/v4"
+)
+
+// timestampStorage keeps the tokens of the node's timestamp authority in
+// the timestamp_tokens table
+type timestampStorage struct {
+	db *DB
+}
+
+// TimestampStorage returns the database-backed timestamp token storage
+func (db *DB) TimestampStorage() timestamp.Storage {
+	return &timestampStorage{db: db}
+}
+
+// LastToken returns the token with the highest serial, or nil
+func (s *timestampStorage) LastToken(ctx context.Context) (*timestamp.Token, error) {
+	var document []byte
+	err := s.db.pool.QueryRow(ctx,
+		`SELECT token FROM timestamp_tokens ORDER BY serial DESC LIMIT 1`).Scan(&document)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timestamp token: %w", err)
+	}
+
+	token := &timestamp.Token{}
+	if err := json.Unmarshal(document, token); err != nil {
+		return nil, fmt.Errorf("failed to decode timestamp token: %w", err)
+	}
+	return token, nil
+}
+
+// AppendToken inserts a token. The serial primary key rejects a second
+// token for the same serial, so concurrent authorities cannot fork the
+// chain.
+func (s *timestampStorage) AppendToken(ctx context.Context, token *timestamp.Token) error {
+	document, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode timestamp token: %w", err)
+	}
+
+	_, err = s.db.pool.Exec(ctx,
+		`INSERT INTO timestamp_tokens (serial, imprint, gen_time, token)
+		 VALUES ($1, $2, $3, $4)`,
+		token.Serial, token.Imprint, token.Time, document)
+	if err != nil {
+		return fmt.Errorf("failed to insert timestamp token: %w", err)
+	}
+	return nil
+}
+
+// Tokens returns the tokens with serials from to to, inclusive
+func (s *timestampStorage) Tokens(ctx context.Context, from, to uint64) ([]*timestamp.Token, error) {
+	rows, err := s.db.pool.Query(ctx,
+		`SELECT token FROM timestamp_tokens
+		 WHERE serial BETWEEN $1 AND $2 ORDER BY serial`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timestamp tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*timestamp.Token, 0)
+	for rows.Next() {
+		var document []byte
+		if err := rows.Scan(&document); err != nil {
+			return nil, fmt.Errorf("failed to scan timestamp token: %w", err)
+		}
+		token := &timestamp.Token{}
+		if err := json.Unmarshal(document, token); err != nil {
+			return nil, fmt.Errorf("failed to decode timestamp token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
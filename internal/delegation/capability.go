@@ -0,0 +1,207 @@
+package delegation
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+)
+
+// TypeCapability is the type of delegation records
+const TypeCapability = "AxiomaticCapability"
+
+var (
+	ErrInvalidSignature = errors.New("invalid delegation signature")
+	ErrBrokenChain      = errors.New("delegation chain is broken")
+	ErrNotValid         = errors.New("delegation is not valid at claim time")
+	ErrOutOfScope       = errors.New("claim is outside the delegated scope")
+	ErrScopeEscalation  = errors.New("delegation exceeds the delegator's own scope")
+)
+
+// Scope limits the claims a delegate may sign. Empty fields do not
+// restrict anything.
+type Scope struct {
+	Tags          []string `json:"tags,omitempty"`
+	Subjects      []string `json:"subjects,omitempty"`
+	MaxConfidence *float64 `json:"maxConfidence,omitempty"`
+}
+
+// Use describes a claim signed under a delegation
+type Use struct {
+	Subject    string
+	Tags       []string
+	Confidence float64
+	Time       time.Time
+}
+
+// Permits checks that a claim falls within the scope: its subject is
+// listed, all of its tags are listed and its confidence is not above the
+// maximum
+func (s Scope) Permits(use Use) error {
+	if len(s.Subjects) > 0 && !contains(s.Subjects, use.Subject) {
+		return fmt.Errorf("%w: subject %s", ErrOutOfScope, use.Subject)
+	}
+	if len(s.Tags) > 0 {
+		for _, tag := range use.Tags {
+			if !contains(s.Tags, tag) {
+				return fmt.Errorf("%w: tag %s", ErrOutOfScope, tag)
+			}
+		}
+	}
+	if s.MaxConfidence != nil && use.Confidence > *s.MaxConfidence {
+		return fmt.Errorf("%w: confidence %g above %g", ErrOutOfScope, use.Confidence, *s.MaxConfidence)
+	}
+	return nil
+}
+
+// Within reports whether s allows nothing that parent does not
+func (s Scope) Within(parent Scope) bool {
+	if len(parent.Subjects) > 0 && (len(s.Subjects) == 0 || !subset(s.Subjects, parent.Subjects)) {
+		return false
+	}
+	if len(parent.Tags) > 0 && (len(s.Tags) == 0 || !subset(s.Tags, parent.Tags)) {
+		return false
+	}
+	if parent.MaxConfidence != nil && (s.MaxConfidence == nil || *s.MaxConfidence > *parent.MaxConfidence) {
+		return false
+	}
+	return true
+}
+
+// Capability lets the delegate key sign claims on behalf of issuer within
+// a scope and time window. It is signed by the delegator key, which is
+// either the issuer's root key or the delegate of a parent capability.
+type Capability struct {
+	Type      string    `json:"type"`
+	Issuer    string    `json:"issuer"`
+	Delegator string    `json:"delegator"`
+	Delegate  string    `json:"delegate"`
+	Scope     Scope     `json:"scope"`
+	NotBefore time.Time `json:"notBefore"`
+	Expires   time.Time `json:"expires"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// Delegate signs a capability for the delegate key. When parent is set the
+// signer must be the parent's delegate, and the new capability may not
+// outlive or widen it.
+func Delegate(issuer string, signer crypto.Signer, delegate ed25519.PublicKey, scope Scope, notBefore, expires time.Time, parent *Capability) (*Capability, error) {
+	if !expires.After(notBefore) {
+		return nil, fmt.Errorf("delegation expires before it starts")
+	}
+
+	capability := &Capability{
+		Type:      TypeCapability,
+		Issuer:    issuer,
+		Delegator: did.FromPublicKey(signer.PublicKey()),
+		Delegate:  did.FromPublicKey(delegate),
+		Scope:     scope,
+		NotBefore: notBefore.UTC().Truncate(time.Second),
+		Expires:   expires.UTC().Truncate(time.Second),
+	}
+
+	if parent != nil {
+		if parent.Issuer != issuer || parent.Delegate != capability.Delegator {
+			return nil, fmt.Errorf("%w: signer is not the parent's delegate", ErrBrokenChain)
+		}
+		if !scope.Within(parent.Scope) || capability.NotBefore.Before(parent.NotBefore) || capability.Expires.After(parent.Expires) {
+			return nil, ErrScopeEscalation
+		}
+	}
+
+	signature, err := sign(capability.signingPayload(), signer)
+	if err != nil {
+		return nil, err
+	}
+	capability.Signature = signature
+	return capability, nil
+}
+
+func (c *Capability) signingPayload() *Capability {
+	payload := *c
+	payload.Signature = ""
+	return &payload
+}
+
+// Verify checks the capability's signature by its delegator
+func (c *Capability) Verify() error {
+	if c.Type != TypeCapability {
+		return fmt.Errorf("%w: unexpected type %s", ErrInvalidSignature, c.Type)
+	}
+	return verify(c.signingPayload(), c.Signature, c.Delegator)
+}
+
+// VerifyChain checks that chain delegates from the issuer's root key down
+// to key, and that every capability in it permits use. It returns the root
+// key, which the caller must still bind to the issuer.
+func VerifyChain(chain []*Capability, issuer string, key ed25519.PublicKey, use Use) (ed25519.PublicKey, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%w: empty chain", ErrBrokenChain)
+	}
+
+	for i, capability := range chain {
+		if err := capability.Verify(); err != nil {
+			return nil, fmt.Errorf("capability #%d: %w", i+1, err)
+		}
+		if capability.Issuer != issuer {
+			return nil, fmt.Errorf("%w: capability #%d is for %s", ErrBrokenChain, i+1, capability.Issuer)
+		}
+		if i > 0 && capability.Delegator != chain[i-1].Delegate {
+			return nil, fmt.Errorf("%w: capability #%d is not signed by the previous delegate", ErrBrokenChain, i+1)
+		}
+		if use.Time.Before(capability.NotBefore) || !use.Time.Before(capability.Expires) {
+			return nil, fmt.Errorf("%w: capability #%d", ErrNotValid, i+1)
+		}
+		if err := capability.Scope.Permits(use); err != nil {
+			return nil, fmt.Errorf("capability #%d: %w", i+1, err)
+		}
+	}
+
+	if chain[len(chain)-1].Delegate != did.FromPublicKey(key) {
+		return nil, fmt.Errorf("%w: claim is not signed by the final delegate", ErrBrokenChain)
+	}
+	return did.PublicKeyFromDIDKey(chain[0].Delegator)
+}
+
+func sign(payload interface{}, signer crypto.Signer) (string, error) {
+	proof, err := crypto.NewProofGenerator().SignProof(payload, signer)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign delegation record: %w", err)
+	}
+	return proof.Signature, nil
+}
+
+func verify(payload interface{}, signature, keyDID string) error {
+	if signature == "" {
+		return fmt.Errorf("%w: unsigned", ErrInvalidSignature)
+	}
+	publicKey, err := did.PublicKeyFromDIDKey(keyDID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := crypto.NewProofGenerator().VerifySignature(payload, signature, publicKey); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func subset(values, of []string) bool {
+	for _, v := range values {
+		if !contains(of, v) {
+			return false
+		}
+	}
+	return true
+}
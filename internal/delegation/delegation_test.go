@@ -0,0 +1,101 @@
+package delegation
+
+import (
+	"testing"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(t *testing.T) *crypto.KeyPair {
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	return key
+}
+
+func TestVerifyChain(t *testing.T) {
+	root, worker, task := newKey(t), newKey(t), newKey(t)
+	now := time.Now()
+	maxConfidence := 0.8
+
+	first, err := Delegate("agent:alice", root, worker.PublicKey(), Scope{
+		Tags:          []string{"physics", "optics"},
+		MaxConfidence: &maxConfidence,
+	}, now.Add(-time.Hour), now.Add(time.Hour), nil)
+	assert.NoError(t, err)
+
+	second, err := Delegate("agent:alice", worker, task.PublicKey(), Scope{
+		Tags:          []string{"optics"},
+		MaxConfidence: &maxConfidence,
+	}, now.Add(-time.Hour), now.Add(time.Hour), first)
+	assert.NoError(t, err)
+
+	chain := []*Capability{first, second}
+	use := Use{Subject: "did:fact:sky", Tags: []string{"optics"}, Confidence: 0.7, Time: now}
+
+	rootKey, err := VerifyChain(chain, "agent:alice", task.PublicKey(), use)
+	assert.NoError(t, err)
+	assert.Equal(t, root.PublicKey(), rootKey)
+
+	outOfScope := use
+	outOfScope.Tags = []string{"physics"}
+	_, err = VerifyChain(chain, "agent:alice", task.PublicKey(), outOfScope)
+	assert.ErrorIs(t, err, ErrOutOfScope)
+
+	tooConfident := use
+	tooConfident.Confidence = 0.9
+	_, err = VerifyChain(chain, "agent:alice", task.PublicKey(), tooConfident)
+	assert.ErrorIs(t, err, ErrOutOfScope)
+
+	expired := use
+	expired.Time = now.Add(2 * time.Hour)
+	_, err = VerifyChain(chain, "agent:alice", task.PublicKey(), expired)
+	assert.ErrorIs(t, err, ErrNotValid)
+
+	_, err = VerifyChain(chain, "agent:alice", worker.PublicKey(), use)
+	assert.ErrorIs(t, err, ErrBrokenChain)
+	_, err = VerifyChain(chain, "agent:bob", task.PublicKey(), use)
+	assert.ErrorIs(t, err, ErrBrokenChain)
+
+	// Delegates cannot grant more than they hold
+	_, err = Delegate("agent:alice", worker, task.PublicKey(), Scope{}, now, now.Add(time.Hour), first)
+	assert.ErrorIs(t, err, ErrScopeEscalation)
+
+	second.Scope.Tags = []string{"physics"}
+	_, err = VerifyChain(chain, "agent:alice", task.PublicKey(), use)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestHistory(t *testing.T) {
+	first, second, third := newKey(t), newKey(t), newKey(t)
+	start := time.Now().Add(-time.Hour)
+	history := NewHistory()
+
+	_, ok := history.KeyAt("agent:alice", start)
+	assert.False(t, ok)
+	assert.NoError(t, history.CheckKey("agent:alice", third.PublicKey(), start))
+
+	rotation, err := Rotate("agent:alice", first, second.PublicKey(), start)
+	assert.NoError(t, err)
+	assert.NoError(t, history.Add(rotation))
+	assert.NoError(t, history.Add(rotation))
+
+	assert.NoError(t, history.CheckKey("agent:alice", first.PublicKey(), start.Add(-time.Minute)))
+	assert.ErrorIs(t, history.CheckKey("agent:alice", first.PublicKey(), start), ErrRotatedKey)
+	assert.NoError(t, history.CheckKey("agent:alice", second.PublicKey(), start))
+
+	// Rotations must continue from the current key
+	stale, err := Rotate("agent:alice", first, third.PublicKey(), start.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, history.Add(stale), ErrRotationOrder)
+
+	next, err := Rotate("agent:alice", second, third.PublicKey(), start.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.NoError(t, history.Add(next))
+
+	current, ok := history.KeyAt("agent:alice", time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, did.FromPublicKey(third.PublicKey()), current)
+}
@@ -0,0 +1,137 @@
+package delegation
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+)
+
+// TypeRotation is the type of key rotation records
+const TypeRotation = "AxiomaticKeyRotation"
+
+var (
+	ErrRotationOrder = errors.New("rotation does not continue the issuer's key history")
+	ErrRotatedKey    = errors.New("key was not the issuer's root key at claim time")
+)
+
+// Rotation replaces an issuer's root key. It is signed by the previous key;
+// from Effective on, only the next key may sign or delegate for the issuer.
+type Rotation struct {
+	Type      string    `json:"type"`
+	Issuer    string    `json:"issuer"`
+	Previous  string    `json:"previous"`
+	Next      string    `json:"next"`
+	Effective time.Time `json:"effective"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// Rotate signs a rotation from the previous key to next
+func Rotate(issuer string, previous crypto.Signer, next ed25519.PublicKey, effective time.Time) (*Rotation, error) {
+	rotation := &Rotation{
+		Type:      TypeRotation,
+		Issuer:    issuer,
+		Previous:  did.FromPublicKey(previous.PublicKey()),
+		Next:      did.FromPublicKey(next),
+		Effective: effective.UTC().Truncate(time.Second),
+	}
+
+	signature, err := sign(rotation.signingPayload(), previous)
+	if err != nil {
+		return nil, err
+	}
+	rotation.Signature = signature
+	return rotation, nil
+}
+
+func (r *Rotation) signingPayload() *Rotation {
+	payload := *r
+	payload.Signature = ""
+	return &payload
+}
+
+// Verify checks the rotation's signature by the previous key
+func (r *Rotation) Verify() error {
+	if r.Type != TypeRotation {
+		return fmt.Errorf("%w: unexpected type %s", ErrInvalidSignature, r.Type)
+	}
+	return verify(r.signingPayload(), r.Signature, r.Previous)
+}
+
+// History tracks the root keys of issuers that have rotated keys
+type History struct {
+	mu        sync.RWMutex
+	rotations map[string][]*Rotation
+}
+
+// NewHistory creates an empty key history
+func NewHistory() *History {
+	return &History{
+		rotations: make(map[string][]*Rotation),
+	}
+}
+
+// Add records a verified rotation. Rotations must be added in order: each
+// one starts from the key the previous one introduced.
+func (h *History) Add(rotation *Rotation) error {
+	if err := rotation.Verify(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rotations := h.rotations[rotation.Issuer]
+	for _, existing := range rotations {
+		if existing.Previous == rotation.Previous {
+			if existing.Next == rotation.Next && existing.Effective.Equal(rotation.Effective) {
+				return nil
+			}
+			return fmt.Errorf("%w: key %s was already rotated", ErrRotationOrder, rotation.Previous)
+		}
+	}
+	if n := len(rotations); n > 0 {
+		last := rotations[n-1]
+		if rotation.Previous != last.Next || !rotation.Effective.After(last.Effective) {
+			return ErrRotationOrder
+		}
+	}
+
+	h.rotations[rotation.Issuer] = append(rotations, rotation)
+	return nil
+}
+
+// KeyAt returns the issuer's root key at time at. It returns false for
+// issuers that have never rotated, whose keys the history knows nothing
+// about.
+func (h *History) KeyAt(issuer string, at time.Time) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	rotations := h.rotations[issuer]
+	if len(rotations) == 0 {
+		return "", false
+	}
+
+	i := sort.Search(len(rotations), func(i int) bool {
+		return rotations[i].Effective.After(at)
+	})
+	if i == 0 {
+		return rotations[0].Previous, true
+	}
+	return rotations[i-1].Next, true
+}
+
+// CheckKey checks that key was the issuer's root key at time at
+func (h *History) CheckKey(issuer string, key ed25519.PublicKey, at time.Time) error {
+	current, ok := h.KeyAt(issuer, at)
+	if !ok || current == did.FromPublicKey(key) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrRotatedKey, issuer)
+}
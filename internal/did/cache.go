@@ -0,0 +1,43 @@
+package did
+
+import (
+	"context"
+	"sync"
+)
+
+// Cache remembers the documents a resolver returns, so that verifying many
+// claims by the same issuers resolves each DID once. Failed resolutions
+// are retried.
+type Cache struct {
+	resolver Resolver
+	mu       sync.Mutex
+	docs     map[string]*Document
+}
+
+// NewCache creates a cache in front of resolver
+func NewCache(resolver Resolver) *Cache {
+	return &Cache{
+		resolver: resolver,
+		docs:     make(map[string]*Document),
+	}
+}
+
+// Resolve returns the cached document of did, resolving it on first use
+func (c *Cache) Resolve(ctx context.Context, did string) (*Document, error) {
+	c.mu.Lock()
+	doc, ok := c.docs[did]
+	c.mu.Unlock()
+	if ok {
+		return doc, nil
+	}
+
+	doc, err := c.resolver.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.docs[did] = doc
+	c.mu.Unlock()
+	return doc, nil
+}
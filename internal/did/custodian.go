@@ -0,0 +1,102 @@
+package did
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNoCustodian = errors.New("no custodian key registered")
+
+// Custodians binds issuers that cannot be resolved, such as custodial
+// twitter: accounts or agent names, to the keys of the custodians that
+// sign for them. DIDs without a registered custodian are resolved by the
+// wrapped resolver.
+type Custodians struct {
+	resolver Resolver
+	keys     map[string][]ed25519.PublicKey
+	// fallback is the custodian of names that are neither DIDs nor
+	// registered
+	fallback ed25519.PublicKey
+}
+
+// NewCustodians creates custodial bindings on top of resolver
+func NewCustodians(resolver Resolver) *Custodians {
+	return &Custodians{
+		resolver: resolver,
+		keys:     make(map[string][]ed25519.PublicKey),
+	}
+}
+
+// SetResolver replaces the resolver of DIDs without a custodian
+func (c *Custodians) SetResolver(resolver Resolver) {
+	c.resolver = resolver
+}
+
+// SetDefault makes publicKey the custodian of every name that is neither
+// a DID nor registered, such as the agents a node signs for with its own
+// key
+func (c *Custodians) SetDefault(publicKey ed25519.PublicKey) {
+	c.fallback = publicKey
+}
+
+// Register allows publicKey to sign for name. A name ending in a colon,
+// such as "twitter:", is a prefix covering every name that starts with it.
+func (c *Custodians) Register(name string, publicKey ed25519.PublicKey) {
+	for _, key := range c.keys[name] {
+		if key.Equal(publicKey) {
+			return
+		}
+	}
+	c.keys[name] = append(c.keys[name], publicKey)
+}
+
+// custodianKeys returns the keys registered for name, for the longest
+// prefix of it or by default
+func (c *Custodians) custodianKeys(name string) ([]ed25519.PublicKey, bool) {
+	if keys, ok := c.keys[name]; ok {
+		return keys, true
+	}
+
+	var keys []ed25519.PublicKey
+	longest := -1
+	for prefix, prefixKeys := range c.keys {
+		if strings.HasSuffix(prefix, ":") && strings.HasPrefix(name, prefix) && len(prefix) > longest {
+			keys, longest = prefixKeys, len(prefix)
+		}
+	}
+	if longest < 0 && c.fallback != nil && !IsDID(name) {
+		return []ed25519.PublicKey{c.fallback}, true
+	}
+	return keys, longest >= 0
+}
+
+// Resolve returns a document listing the custodian keys of name as
+// assertion methods, or resolves a DID without custodian
+func (c *Custodians) Resolve(ctx context.Context, name string) (*Document, error) {
+	keys, ok := c.custodianKeys(name)
+	if !ok {
+		if !IsDID(name) || c.resolver == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNoCustodian, name)
+		}
+		return c.resolver.Resolve(ctx, name)
+	}
+
+	doc := &Document{
+		Context: []string{"https://www.w3.org/ns/did/v1"},
+		ID:      name,
+	}
+	for i, publicKey := range keys {
+		vmID := fmt.Sprintf("%s#custodian-%d", name, i+1)
+		doc.VerificationMethod = append(doc.VerificationMethod, VerificationMethod{
+			ID:                 vmID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         FromPublicKey(publicKey),
+			PublicKeyMultibase: encodeMultikey(publicKey),
+		})
+		doc.AssertionMethod = append(doc.AssertionMethod, vmID)
+	}
+	return doc, nil
+}
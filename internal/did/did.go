@@ -0,0 +1,173 @@
+package did
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidDID        = errors.New("invalid DID")
+	ErrUnsupportedMethod = errors.New("unsupported DID method")
+	ErrNotFound          = errors.New("DID document not found")
+	ErrKeyNotAuthorized  = errors.New("key is not a verification method of DID")
+)
+
+// Document is the subset of a DID document used to verify claims
+type Document struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Authentication     []string             `json:"authentication,omitempty"`
+	AssertionMethod    []string             `json:"assertionMethod,omitempty"`
+}
+
+// VerificationMethod describes a public key controlled by the DID subject
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk       *JWK   `json:"publicKeyJwk,omitempty"`
+}
+
+// JWK is an OKP JSON Web Key as used for Ed25519 verification methods
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// Resolver resolves a DID to its document
+type Resolver interface {
+	Resolve(ctx context.Context, did string) (*Document, error)
+}
+
+// Registry dispatches resolution to a resolver per DID method
+type Registry struct {
+	resolvers map[string]Resolver
+}
+
+// NewRegistry creates a registry that resolves did:key identifiers
+func NewRegistry() *Registry {
+	return &Registry{
+		resolvers: map[string]Resolver{
+			MethodKey: KeyResolver{},
+		},
+	}
+}
+
+// Register adds or replaces the resolver for a DID method
+func (r *Registry) Register(method string, resolver Resolver) {
+	r.resolvers[method] = resolver
+}
+
+// Resolve resolves did using the resolver registered for its method
+func (r *Registry) Resolve(ctx context.Context, did string) (*Document, error) {
+	method, _, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, ok := r.resolvers[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+	return resolver.Resolve(ctx, did)
+}
+
+// IsDID reports whether s looks like a DID
+func IsDID(s string) bool {
+	return strings.HasPrefix(s, "did:")
+}
+
+// Parse splits a DID into its method and method-specific identifier
+func Parse(did string) (method, id string, err error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidDID, did)
+	}
+	return parts[1], parts[2], nil
+}
+
+// PublicKeys returns the Ed25519 keys listed as verification methods
+func (d *Document) PublicKeys() []ed25519.PublicKey {
+	keys := make([]ed25519.PublicKey, 0, len(d.VerificationMethod))
+	for _, vm := range d.VerificationMethod {
+		if key, err := vm.PublicKey(); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// HasKey reports whether publicKey is one of the document's keys
+func (d *Document) HasKey(publicKey ed25519.PublicKey) bool {
+	for _, key := range d.PublicKeys() {
+		if key.Equal(publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAssertionKey reports whether publicKey is one of the document's keys
+// listed under assertionMethod, the keys allowed to issue claims
+func (d *Document) HasAssertionKey(publicKey ed25519.PublicKey) bool {
+	assertion := make(map[string]bool, len(d.AssertionMethod))
+	for _, id := range d.AssertionMethod {
+		assertion[d.absoluteID(id)] = true
+	}
+	for _, vm := range d.VerificationMethod {
+		if !assertion[d.absoluteID(vm.ID)] {
+			continue
+		}
+		if key, err := vm.PublicKey(); err == nil && key.Equal(publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// absoluteID resolves a verification method id relative to the document,
+// such as "#key-1"
+func (d *Document) absoluteID(id string) string {
+	if strings.HasPrefix(id, "#") {
+		return d.ID + id
+	}
+	return id
+}
+
+// PublicKey decodes the Ed25519 key of the verification method
+func (vm *VerificationMethod) PublicKey() (ed25519.PublicKey, error) {
+	if vm.PublicKeyMultibase != "" {
+		return decodeMultikey(vm.PublicKeyMultibase)
+	}
+
+	if vm.PublicKeyJwk != nil && vm.PublicKeyJwk.Kty == "OKP" && vm.PublicKeyJwk.Crv == "Ed25519" {
+		key, err := base64.RawURLEncoding.DecodeString(vm.PublicKeyJwk.X)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid JWK in verification method %s", vm.ID)
+		}
+		return ed25519.PublicKey(key), nil
+	}
+
+	return nil, fmt.Errorf("unsupported verification method %s", vm.ID)
+}
+
+// VerifyKey resolves did and checks that publicKey is one of its assertion
+// methods, allowed to sign claims for it
+func VerifyKey(ctx context.Context, resolver Resolver, did string, publicKey ed25519.PublicKey) error {
+	doc, err := resolver.Resolve(ctx, did)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", did, err)
+	}
+
+	if !doc.HasAssertionKey(publicKey) {
+		return fmt.Errorf("%w: %s", ErrKeyNotAuthorized, did)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"axia/internal/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDKeyRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	id := FromPublicKey(key.PublicKey())
+	assert.True(t, strings.HasPrefix(id, "did:key:z6Mk"))
+
+	publicKey, err := PublicKeyFromDIDKey(id)
+	assert.NoError(t, err)
+	assert.True(t, publicKey.Equal(key.PublicKey()))
+
+	doc, err := NewRegistry().Resolve(context.Background(), id)
+	assert.NoError(t, err)
+	assert.True(t, doc.HasKey(key.PublicKey()))
+
+	other, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	assert.ErrorIs(t, VerifyKey(context.Background(), NewRegistry(), id, other.PublicKey()), ErrKeyNotAuthorized)
+}
+
+func TestWebResolver(t *testing.T) {
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	login, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	var id string
+	var requests int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/agents/alice/did.json" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(Document{
+			ID: id,
+			VerificationMethod: []VerificationMethod{{
+				ID:                 id + "#key-1",
+				Type:               "Ed25519VerificationKey2020",
+				Controller:         id,
+				PublicKeyMultibase: encodeMultikey(key.PublicKey()),
+			}, {
+				ID:                 id + "#key-2",
+				Type:               "Ed25519VerificationKey2020",
+				Controller:         id,
+				PublicKeyMultibase: encodeMultikey(login.PublicKey()),
+			}},
+			Authentication:  []string{"#key-2"},
+			AssertionMethod: []string{"#key-1"},
+		})
+	}))
+	defer server.Close()
+
+	// did:web percent-encodes the port separator
+	host := strings.ReplaceAll(strings.TrimPrefix(server.URL, "https://"), ":", "%3A")
+	id = "did:web:" + host + ":agents:alice"
+
+	registry := NewRegistry()
+	registry.Register(MethodWeb, NewWebResolver(server.Client()))
+
+	assert.NoError(t, VerifyKey(context.Background(), registry, id, key.PublicKey()))
+	// Keys for authentication only may not sign claims
+	assert.ErrorIs(t, VerifyKey(context.Background(), registry, id, login.PublicKey()), ErrKeyNotAuthorized)
+
+	_, err = registry.Resolve(context.Background(), "did:web:"+host+":agents:bob")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// A cache fetches each document once
+	cache := NewCache(registry)
+	fetched := atomic.LoadInt32(&requests)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, VerifyKey(context.Background(), cache, id, key.PublicKey()))
+	}
+	assert.Equal(t, fetched+1, atomic.LoadInt32(&requests))
+}
+
+func TestWebDocumentURL(t *testing.T) {
+	docURL, err := WebDocumentURL("did:web:example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/.well-known/did.json", docURL)
+
+	docURL, err = WebDocumentURL("did:web:example.com%3A8443:users:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com:8443/users/alice/did.json", docURL)
+
+	_, err = WebDocumentURL("did:key:z6Mk")
+	assert.ErrorIs(t, err, ErrUnsupportedMethod)
+
+	// Encoded separators cannot redirect the request
+	for _, id := range []string{
+		"did:web:example.com%2Fevil.com",
+		"did:web:example.com%40evil.com",
+		"did:web:evil.com%3Fexample.com",
+		"did:web:evil.com%23example.com",
+		"did:web:example.com:users%2F..%2Fadmin",
+		"did:web:example.com:..",
+	} {
+		_, err = WebDocumentURL(id)
+		assert.ErrorIs(t, err, ErrInvalidDID, id)
+	}
+}
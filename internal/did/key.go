@@ -0,0 +1,165 @@
+package did
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"axia/internal/crypto"
+)
+
+// MethodKey is the did:key method name
+const MethodKey = "key"
+
+// Multicodec codes of the public key types did:key identifiers may encode
+const (
+	multicodecEd25519Pub = 0xed
+	multicodecX25519Pub  = 0xec
+	multicodecP256Pub    = 0x1200
+)
+
+// FromPublicKey derives the did:key identifier of an Ed25519 public key
+func FromPublicKey(publicKey ed25519.PublicKey) string {
+	return "did:key:" + encodeMultikey(publicKey)
+}
+
+// PublicKeyFromDIDKey extracts the Ed25519 key encoded in a did:key
+func PublicKeyFromDIDKey(did string) (ed25519.PublicKey, error) {
+	method, id, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != MethodKey {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+	return decodeMultikey(id)
+}
+
+// FromP256PublicKey derives the did:key identifier of a P-256 public key
+func FromP256PublicKey(publicKey *ecdsa.PublicKey) string {
+	prefix := binary.AppendUvarint(nil, multicodecP256Pub)
+	point := elliptic.MarshalCompressed(elliptic.P256(), publicKey.X, publicKey.Y)
+	return "did:key:" + crypto.EncodeMultibase(append(prefix, point...))
+}
+
+// ParseKey extracts the key encoded in a did:key or did:key verification
+// method URL. The result is an ed25519.PublicKey or a P-256 *ecdsa.PublicKey.
+func ParseKey(did string) (stdcrypto.PublicKey, error) {
+	did, _, _ = strings.Cut(did, "#")
+	method, id, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != MethodKey {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+
+	data, err := crypto.DecodeMultibase(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDID, err)
+	}
+
+	code, n := binary.Uvarint(data)
+	switch {
+	case n <= 0:
+	case code == multicodecEd25519Pub:
+		return decodeMultikey(id)
+	case code == multicodecP256Pub:
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), data[n:])
+		if x != nil {
+			return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unsupported key type", ErrInvalidDID)
+}
+
+// KeyAgreementKey returns the X25519 public key data can be encrypted to
+// for a did:key. Ed25519 keys are converted to their X25519 form.
+func KeyAgreementKey(did string) ([]byte, error) {
+	did, _, _ = strings.Cut(did, "#")
+	method, id, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+	if method != MethodKey {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+
+	data, err := crypto.DecodeMultibase(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDID, err)
+	}
+
+	code, n := binary.Uvarint(data)
+	switch {
+	case n <= 0:
+	case code == multicodecEd25519Pub:
+		publicKey, err := decodeMultikey(id)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.X25519PublicKey(publicKey)
+	case code == multicodecX25519Pub && len(data)-n == 32:
+		return data[n:], nil
+	}
+	return nil, fmt.Errorf("%w: no key agreement key", ErrInvalidDID)
+}
+
+// VerificationMethodID returns the URL of the single verification method
+// of a did:key, which uses the key's multibase encoding as fragment
+func VerificationMethodID(did string) string {
+	return did + "#" + strings.TrimPrefix(did, "did:key:")
+}
+
+// KeyResolver resolves did:key identifiers without any network access
+type KeyResolver struct{}
+
+// Resolve expands a did:key into a document with a single Ed25519 key
+func (KeyResolver) Resolve(ctx context.Context, did string) (*Document, error) {
+	publicKey, err := PublicKeyFromDIDKey(did)
+	if err != nil {
+		return nil, err
+	}
+
+	multikey := encodeMultikey(publicKey)
+	vmID := did + "#" + multikey
+
+	return &Document{
+		Context: []string{
+			"https://www.w3.org/ns/did/v1",
+			"https://w3id.org/security/suites/ed25519-2020/v1",
+		},
+		ID: did,
+		VerificationMethod: []VerificationMethod{{
+			ID:                 vmID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         did,
+			PublicKeyMultibase: multikey,
+		}},
+		Authentication:  []string{vmID},
+		AssertionMethod: []string{vmID},
+	}, nil
+}
+
+func encodeMultikey(publicKey ed25519.PublicKey) string {
+	prefix := binary.AppendUvarint(nil, multicodecEd25519Pub)
+	return crypto.EncodeMultibase(append(prefix, publicKey...))
+}
+
+func decodeMultikey(s string) (ed25519.PublicKey, error) {
+	data, err := crypto.DecodeMultibase(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDID, err)
+	}
+
+	code, n := binary.Uvarint(data)
+	if n <= 0 || code != multicodecEd25519Pub || len(data)-n != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: not an Ed25519 multikey", ErrInvalidDID)
+	}
+	return ed25519.PublicKey(data[n:]), nil
+}
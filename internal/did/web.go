@@ -0,0 +1,97 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MethodWeb is the did:web method name
+const MethodWeb = "web"
+
+// WebResolver resolves did:web identifiers over HTTPS
+type WebResolver struct {
+	client *http.Client
+}
+
+// NewWebResolver creates a did:web resolver; a nil client uses a default
+// client with a short timeout
+func NewWebResolver(client *http.Client) *WebResolver {
+	if client == nil {
+		client = &http.Client{
+			Timeout: time.Second * 10,
+		}
+	}
+	return &WebResolver{client: client}
+}
+
+// Resolve fetches the DID document for a did:web identifier
+func (w *WebResolver) Resolve(ctx context.Context, did string) (*Document, error) {
+	docURL, err := WebDocumentURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DID document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, did)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch DID document: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var doc Document
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode DID document: %w", err)
+	}
+
+	if doc.ID != did {
+		return nil, fmt.Errorf("%w: document id %q does not match %s", ErrInvalidDID, doc.ID, did)
+	}
+	return &doc, nil
+}
+
+// WebDocumentURL maps a did:web identifier to the URL of its document
+func WebDocumentURL(did string) (string, error) {
+	method, id, err := Parse(did)
+	if err != nil {
+		return "", err
+	}
+	if method != MethodWeb {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+
+	// Decoded segments must not smuggle in another host, path, query or
+	// fragment
+	segments := strings.Split(id, ":")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil || decoded == "" || decoded == "." || decoded == ".." || strings.ContainsAny(decoded, `/\@?#`) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidDID, did)
+		}
+		segments[i] = decoded
+	}
+
+	host := segments[0]
+	if len(segments) == 1 {
+		return "https://" + host + "/.well-known/did.json", nil
+	}
+	return "https://" + host + "/" + strings.Join(segments[1:], "/") + "/did.json", nil
+}
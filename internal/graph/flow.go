@@ -0,0 +1,131 @@
+package graph
+
+// DefaultFlowCapacities are Advogato's node capacities by distance from the
+// seeds
+var DefaultFlowCapacities = []int{800, 200, 200, 50, 12, 4, 2, 1}
+
+// arc is an edge of the residual flow network
+type arc struct {
+	to       int
+	reverse  int
+	capacity int
+}
+
+// flowNetwork is a residual network solved by Edmonds-Karp
+type flowNetwork struct {
+	arcs [][]arc
+}
+
+func (f *flowNetwork) add(from, to, capacity int) {
+	f.arcs[from] = append(f.arcs[from], arc{to: to, reverse: len(f.arcs[to]), capacity: capacity})
+	f.arcs[to] = append(f.arcs[to], arc{to: from, reverse: len(f.arcs[from]) - 1})
+}
+
+// maxFlow pushes as much flow as possible from source to sink along
+// shortest augmenting paths
+func (f *flowNetwork) maxFlow(source, sink int) {
+	for {
+		// previous holds the vertex and arc each vertex was reached by
+		type step struct{ vertex, arc int }
+		previous := make([]*step, len(f.arcs))
+		previous[source] = &step{vertex: -1}
+		queue := []int{source}
+		for len(queue) > 0 && previous[sink] == nil {
+			vertex := queue[0]
+			queue = queue[1:]
+			for i, a := range f.arcs[vertex] {
+				if a.capacity > 0 && previous[a.to] == nil {
+					previous[a.to] = &step{vertex: vertex, arc: i}
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		if previous[sink] == nil {
+			return
+		}
+
+		bottleneck := -1
+		for v := sink; v != source; v = previous[v].vertex {
+			a := f.arcs[previous[v].vertex][previous[v].arc]
+			if bottleneck < 0 || a.capacity < bottleneck {
+				bottleneck = a.capacity
+			}
+		}
+		for v := sink; v != source; v = previous[v].vertex {
+			a := &f.arcs[previous[v].vertex][previous[v].arc]
+			a.capacity -= bottleneck
+			f.arcs[v][a.reverse].capacity += bottleneck
+		}
+	}
+}
+
+// FlowTrust runs the Advogato trust metric (Levien, 2004) from the seeds and
+// returns the nodes it accepts. Every node gets a capacity by its distance
+// from the seeds, capacities[0] for the seeds themselves; nodes beyond the
+// last capacity are not considered. Each node keeps one unit of the flow
+// reaching it to be accepted and passes up to its capacity minus one on
+// along the followed edges. Since all trust into a group of nodes flows
+// through the edges into it, the number of such edges bounds how many of
+// them are accepted however many nodes it contains. follow selects the
+// edges that carry trust; nil follows all edges.
+func (g *Graph) FlowTrust(seeds []*Node, capacities []int, follow func(*Edge) bool) map[*Node]bool {
+	accepted := make(map[*Node]bool)
+	if len(seeds) == 0 || len(capacities) == 0 {
+		return accepted
+	}
+
+	// Distance from the nearest seed
+	distances := make(map[*Node]int)
+	for _, seed := range seeds {
+		for node, hops := range g.Reachable(seed, len(capacities)-1, follow) {
+			if previous, ok := distances[node]; !ok || hops < previous {
+				distances[node] = hops
+			}
+		}
+	}
+
+	// Every node x is split into x- (2i) and x+ (2i+1): x- -> sink with
+	// capacity 1 accepts x, x- -> x+ passes the rest of its capacity on
+	nodes := make([]*Node, 0, len(distances))
+	index := make(map[*Node]int, len(distances))
+	for _, node := range g.Nodes {
+		if _, ok := distances[node]; ok {
+			index[node] = len(nodes)
+			nodes = append(nodes, node)
+		}
+	}
+	source, sink := 2*len(nodes), 2*len(nodes)+1
+	network := &flowNetwork{arcs: make([][]arc, 2*len(nodes)+2)}
+
+	// No edge can carry more than all the seeds' capacity
+	unlimited := 0
+	for _, seed := range seeds {
+		if i, ok := index[seed]; ok {
+			network.add(source, 2*i, capacities[0])
+			unlimited += capacities[0]
+		}
+	}
+	for i, node := range nodes {
+		network.add(2*i, sink, 1)
+		if capacity := capacities[distances[node]]; capacity > 1 {
+			network.add(2*i, 2*i+1, capacity-1)
+		}
+		for _, edge := range g.outgoing[node] {
+			j, ok := index[edge.To]
+			if !ok || (follow != nil && !follow(edge)) {
+				continue
+			}
+			network.add(2*i+1, 2*j, unlimited)
+		}
+	}
+	network.maxFlow(source, sink)
+
+	for i, node := range nodes {
+		for _, a := range network.arcs[2*i] {
+			if a.to == sink && a.capacity == 0 {
+				accepted[node] = true
+			}
+		}
+	}
+	return accepted
+}
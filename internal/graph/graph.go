@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"axia/internal/crypto"
 	"axia/internal/state"
@@ -18,6 +19,7 @@ type Edge struct {
 	From        *Node
 	To          *Node
 	Weight      float64
+	Data        interface{}
 	State       *state.StateManager
 	Proof       *crypto.Proof
 	logger      *logrus.Logger
@@ -26,6 +28,10 @@ type Edge struct {
 type Graph struct {
 	Nodes       []*Node
 	Edges       []*Edge
+	// index finds nodes by their string data, outgoing holds the edges
+	// leaving each node
+	index       map[string]*Node
+	outgoing    map[*Node][]*Edge
 	proofGen    *crypto.ProofGenerator
 	logger      *logrus.Logger
 }
@@ -34,12 +40,22 @@ func NewGraph(logger *logrus.Logger) *Graph {
 	return &Graph{
 		Nodes:    make([]*Node, 0),
 		Edges:    make([]*Edge, 0),
+		index:    make(map[string]*Node),
+		outgoing: make(map[*Node][]*Edge),
 		proofGen: crypto.NewProofGenerator(),
 		logger:   logger,
 	}
 }
 
+// AddNode adds a node holding data. Nodes with string data, such as agent
+// and subject identifiers, are unique: adding one again returns the node
+// already in the graph.
 func (g *Graph) AddNode(data interface{}) (*Node, error) {
+	key, isKey := data.(string)
+	if node, ok := g.index[key]; isKey && ok {
+		return node, nil
+	}
+
 	g.logger.WithField("data", data).Info("Adding new node")
 	
 	node := &Node{
@@ -57,6 +73,9 @@ func (g *Graph) AddNode(data interface{}) (*Node, error) {
 	
 	node.Proof = proof
 	g.Nodes = append(g.Nodes, node)
+	if isKey {
+		g.index[key] = node
+	}
 	
 	g.logger.WithFields(logrus.Fields{
 		"node_id": node.ID,
@@ -64,4 +83,30 @@ func (g *Graph) AddNode(data interface{}) (*Node, error) {
 	}).Info("Node added successfully")
 	
 	return node, nil
-} 
\ No newline at end of file
+}
+
+// Lookup returns the node holding the given string data
+func (g *Graph) Lookup(data string) (*Node, bool) {
+	node, ok := g.index[data]
+	return node, ok
+}
+
+// AddEdge adds a weighted edge between two nodes of the graph. data is
+// what the edge stands for, such as the claim that created it.
+func (g *Graph) AddEdge(from, to *Node, weight float64, data interface{}) *Edge {
+	edge := &Edge{
+		From:   from,
+		To:     to,
+		Weight: weight,
+		Data:   data,
+		logger: g.logger,
+	}
+	g.Edges = append(g.Edges, edge)
+	g.outgoing[from] = append(g.outgoing[from], edge)
+	return edge
+}
+
+// Outgoing returns the edges leaving node
+func (g *Graph) Outgoing(node *Node) []*Edge {
+	return g.outgoing[node]
+}
@@ -0,0 +1,26 @@
+package graph
+
+// DefaultRestartProbability is the chance that a personalized PageRank
+// walk jumps back to its source at each step
+const DefaultRestartProbability = 0.15
+
+// PersonalizedPageRank returns the probability of finding a random walk
+// from source at each node. The walk follows edges in proportion to their
+// weight and restarts at source with probability restart at every step,
+// or whenever it reaches a node without followed edges. Nodes unreachable
+// from source score 0; scores sum to 1. follow selects the edges walked and
+// weigh returns their weight; nil follows all edges by Edge.Weight.
+func (g *Graph) PersonalizedPageRank(source *Node, restart float64, follow func(*Edge) bool, weigh func(*Edge) float64) map[*Node]float64 {
+	if source == nil {
+		return make(map[*Node]float64)
+	}
+	// This is EigenTrust with all pretrust on the source
+	return g.EigenTrust(EigenTrustParams{
+		Pretrusted:     []*Node{source},
+		PretrustWeight: restart,
+		Tolerance:      DefaultTolerance,
+		MaxIterations:  DefaultMaxIterations,
+		Follow:         follow,
+		Weigh:          weigh,
+	})
+}
@@ -0,0 +1,27 @@
+package graph
+
+// Reachable walks the graph breadth-first from a node, following the edges
+// accepted by follow for at most depth hops. It returns the hop distance of
+// every node reached, the start node included at distance 0.
+func (g *Graph) Reachable(from *Node, depth int, follow func(*Edge) bool) map[*Node]int {
+	distances := map[*Node]int{from: 0}
+	frontier := []*Node{from}
+
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		next := make([]*Node, 0)
+		for _, node := range frontier {
+			for _, edge := range g.outgoing[node] {
+				if _, seen := distances[edge.To]; seen {
+					continue
+				}
+				if follow != nil && !follow(edge) {
+					continue
+				}
+				distances[edge.To] = hop
+				next = append(next, edge.To)
+			}
+		}
+		frontier = next
+	}
+	return distances
+}
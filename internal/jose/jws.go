@@ -0,0 +1,215 @@
+package jose
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+)
+
+// Supported JWS algorithms
+const (
+	AlgEdDSA = "EdDSA"
+	AlgES256 = "ES256"
+)
+
+var (
+	ErrMalformed            = errors.New("malformed JWS")
+	ErrUnsupportedAlgorithm = errors.New("unsupported JWS algorithm")
+	ErrInvalidSignature     = errors.New("invalid JWS signature")
+)
+
+// Header is the protected header of a JWS
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Signer produces JWS signatures with a single key
+type Signer interface {
+	Algorithm() string
+	KeyID() string
+	Sign(signingInput []byte) ([]byte, error)
+}
+
+type edDSASigner struct {
+	signer crypto.Signer
+}
+
+// NewEdDSASigner returns a JWS signer for an Ed25519 key. Its key ID is the
+// did:key verification method of the key.
+func NewEdDSASigner(signer crypto.Signer) Signer {
+	return &edDSASigner{signer: signer}
+}
+
+func (s *edDSASigner) Algorithm() string { return AlgEdDSA }
+
+func (s *edDSASigner) KeyID() string {
+	return did.VerificationMethodID(did.FromPublicKey(s.signer.PublicKey()))
+}
+
+func (s *edDSASigner) Sign(signingInput []byte) ([]byte, error) {
+	return s.signer.Sign(signingInput)
+}
+
+type es256Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewES256Signer returns a JWS signer for a P-256 key. Its key ID is the
+// did:key verification method of the key.
+func NewES256Signer(key *ecdsa.PrivateKey) (Signer, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%w: ES256 requires a P-256 key", ErrUnsupportedAlgorithm)
+	}
+	return &es256Signer{key: key}, nil
+}
+
+func (s *es256Signer) Algorithm() string { return AlgES256 }
+
+func (s *es256Signer) KeyID() string {
+	return did.VerificationMethodID(did.FromP256PublicKey(&s.key.PublicKey))
+}
+
+func (s *es256Signer) Sign(signingInput []byte) ([]byte, error) {
+	digest := sha256.Sum256(signingInput)
+	r, sv, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// JWS uses the fixed-width R || S encoding rather than ASN.1
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sv.FillBytes(signature[32:])
+	return signature, nil
+}
+
+// Sign creates a compact JWS over payload
+func Sign(payload []byte, typ string, signer Signer) (string, error) {
+	header, err := json.Marshal(Header{
+		Algorithm: signer.Algorithm(),
+		Type:      typ,
+		KeyID:     signer.KeyID(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(header) + "." + encode(payload)
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWS: %w", err)
+	}
+	return signingInput + "." + encode(signature), nil
+}
+
+// JWS is a parsed compact JWS
+type JWS struct {
+	Header    Header
+	Payload   []byte
+	Signature []byte
+
+	signingInput string
+}
+
+// IsCompact reports whether s looks like a compact JWS
+func IsCompact(s string) bool {
+	return strings.Count(s, ".") == 2 && strings.HasPrefix(s, "eyJ")
+}
+
+// Parse decodes a compact JWS without checking its signature
+func Parse(token string) (*JWS, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected three segments", ErrMalformed)
+	}
+
+	headerJSON, err := decode(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformed, err)
+	}
+	payload, err := decode(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrMalformed, err)
+	}
+	signature, err := decode(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrMalformed, err)
+	}
+
+	jws := &JWS{
+		Payload:      payload,
+		Signature:    signature,
+		signingInput: parts[0] + "." + parts[1],
+	}
+	if err := json.Unmarshal(headerJSON, &jws.Header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformed, err)
+	}
+	return jws, nil
+}
+
+// Verify checks the signature with the key named by the header's kid,
+// which must be a did:key verification method
+func (j *JWS) Verify() error {
+	key, err := did.ParseKey(j.Header.KeyID)
+	if err != nil {
+		return fmt.Errorf("unusable kid %q: %w", j.Header.KeyID, err)
+	}
+	return j.VerifyWithKey(key)
+}
+
+// VerifyWithKey checks the signature with the given public key, which must
+// match the header's algorithm
+func (j *JWS) VerifyWithKey(key stdcrypto.PublicKey) error {
+	switch j.Header.Algorithm {
+	case AlgEdDSA:
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: EdDSA requires an Ed25519 key", ErrUnsupportedAlgorithm)
+		}
+		if err := crypto.Verify(publicKey, []byte(j.signingInput), j.Signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case AlgES256:
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || publicKey.Curve != elliptic.P256() {
+			return fmt.Errorf("%w: ES256 requires a P-256 key", ErrUnsupportedAlgorithm)
+		}
+		if len(j.Signature) != 64 {
+			return ErrInvalidSignature
+		}
+		digest := sha256.Sum256([]byte(j.signingInput))
+		r := new(big.Int).SetBytes(j.Signature[:32])
+		s := new(big.Int).SetBytes(j.Signature[32:])
+		if !ecdsa.Verify(publicKey, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, j.Header.Algorithm)
+	}
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
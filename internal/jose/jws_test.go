@@ -0,0 +1,84 @@
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"axia/internal/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSigners(t *testing.T) []Signer {
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	es256, err := NewES256Signer(p256)
+	assert.NoError(t, err)
+	return []Signer{NewEdDSASigner(key), es256}
+}
+
+// withHeader replaces the protected header of token, keeping its payload
+// and signature
+func withHeader(t *testing.T, token string, header Header) string {
+	data, err := json.Marshal(header)
+	assert.NoError(t, err)
+	parts := strings.Split(token, ".")
+	return encode(data) + "." + parts[1] + "." + parts[2]
+}
+
+func TestSignVerify(t *testing.T) {
+	payload := []byte(`{"sub":"did:fact:sky"}`)
+
+	for _, signer := range newSigners(t) {
+		token, err := Sign(payload, "JWT", signer)
+		assert.NoError(t, err)
+		assert.True(t, IsCompact(token))
+
+		jws, err := Parse(token)
+		assert.NoError(t, err, signer.Algorithm())
+		assert.Equal(t, signer.Algorithm(), jws.Header.Algorithm)
+		assert.Equal(t, signer.KeyID(), jws.Header.KeyID)
+		assert.Equal(t, payload, jws.Payload)
+		assert.NoError(t, jws.Verify(), signer.Algorithm())
+
+		// The signature covers the payload
+		parts := strings.Split(token, ".")
+		tampered, err := Parse(parts[0] + "." + encode([]byte(`{"sub":"did:fact:sea"}`)) + "." + parts[2])
+		assert.NoError(t, err)
+		assert.ErrorIs(t, tampered.Verify(), ErrInvalidSignature, signer.Algorithm())
+	}
+}
+
+func TestVerifyRejectsAlgorithms(t *testing.T) {
+	signers := newSigners(t)
+	edDSA, es256 := signers[0], signers[1]
+	token, err := Sign([]byte(`{}`), "JWT", edDSA)
+	assert.NoError(t, err)
+
+	// The algorithm must match the key named by kid
+	wrong, err := Parse(withHeader(t, token, Header{Algorithm: AlgES256, Type: "JWT", KeyID: edDSA.KeyID()}))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, wrong.Verify(), ErrUnsupportedAlgorithm)
+
+	other, err := Sign([]byte(`{}`), "JWT", es256)
+	assert.NoError(t, err)
+	swapped, err := Parse(withHeader(t, other, Header{Algorithm: AlgEdDSA, Type: "JWT", KeyID: es256.KeyID()}))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, swapped.Verify(), ErrUnsupportedAlgorithm)
+
+	// Unsigned tokens are never accepted, with or without a signature
+	for _, signature := range []string{"", strings.Split(token, ".")[2]} {
+		parts := strings.Split(withHeader(t, token, Header{Algorithm: "none", Type: "JWT", KeyID: edDSA.KeyID()}), ".")
+		unsigned, err := Parse(parts[0] + "." + parts[1] + "." + signature)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, unsigned.Verify(), ErrUnsupportedAlgorithm)
+	}
+
+	_, err = Parse("eyJhbGciOiJub25lIn0.e30")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
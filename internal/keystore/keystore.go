@@ -0,0 +1,409 @@
+package keystore
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrKeyExists       = errors.New("key already exists")
+	ErrInvalidName     = errors.New("key name may only contain letters, digits, '.', '_' and '-'")
+	ErrEmptyPassphrase = errors.New("passphrase must not be empty")
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted key file")
+	ErrUnsupportedKDF  = errors.New("unsupported key derivation function")
+	ErrKDFParams       = errors.New("key derivation parameters out of bounds")
+	ErrTamperedKey     = errors.New("key file name or agent does not match its MAC")
+)
+
+const (
+	kdfScrypt    = "scrypt"
+	cipherSecret = "xsalsa20-poly1305"
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	// Key files may come from untrusted sources through Import, so Unlock
+	// refuses parameters taking more than 1 GiB of memory per pass
+	maxScryptN = 1 << 20
+	maxScryptR = 8
+	maxScryptP = 4
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// EncryptedKey is an agent signing key as stored on disk
+type EncryptedKey struct {
+	Name      string    `json:"name"`
+	Agent     string    `json:"agent"`
+	PublicKey string    `json:"publicKey"`
+	Created   time.Time `json:"created"`
+	Crypto    Cipher    `json:"crypto"`
+
+	// Retired is set once the key has been rotated out; retired keys are
+	// kept but no longer used to sign for their agent
+	Retired *time.Time `json:"retired,omitempty"`
+}
+
+// Cipher holds the parameters needed to decrypt a key seed
+type Cipher struct {
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+	Cipher     string    `json:"cipher"`
+	Nonce      string    `json:"nonce"`
+	Ciphertext string    `json:"ciphertext"`
+	// MAC authenticates the key's name, agent and public key, which are
+	// stored in the clear
+	MAC string `json:"mac"`
+}
+
+// KDFParams are the scrypt parameters used to derive the encryption key
+type KDFParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+// Keystore manages passphrase-encrypted agent keys in a directory
+type Keystore struct {
+	dir    string
+	logger *logrus.Logger
+}
+
+// DefaultDir returns the default keystore location
+func DefaultDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".axia-cli", "keys")
+}
+
+// New opens the keystore at dir, creating it if needed
+func New(dir string, logger *logrus.Logger) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+
+	return &Keystore{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+// Generate creates a new key for agent and stores it under name. An empty
+// agent defaults to the did:key identifier of the new key.
+func (ks *Keystore) Generate(name, agent, passphrase string) (*EncryptedKey, error) {
+	pair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	if agent == "" {
+		agent = did.FromPublicKey(pair.PublicKey())
+	}
+	return ks.Add(name, agent, pair, passphrase)
+}
+
+// Add encrypts an existing key pair and stores it under name
+func (ks *Keystore) Add(name, agent string, pair *crypto.KeyPair, passphrase string) (*EncryptedKey, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+	if _, err := os.Stat(ks.path(name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyExists, name)
+	}
+
+	key, err := encrypt(name, agent, pair, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key.Created = time.Now().UTC()
+
+	if err := ks.write(key); err != nil {
+		return nil, err
+	}
+
+	ks.logger.WithFields(logrus.Fields{
+		"name":  name,
+		"agent": agent,
+	}).Info("Stored agent key")
+
+	return key, nil
+}
+
+// Import stores a key file previously produced by Export
+func (ks *Keystore) Import(data []byte) (*EncryptedKey, error) {
+	var key EncryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	if !namePattern.MatchString(key.Name) {
+		return nil, ErrInvalidName
+	}
+	if _, err := os.Stat(ks.path(key.Name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyExists, key.Name)
+	}
+
+	if err := ks.write(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Export returns the encrypted key file for name
+func (ks *Keystore) Export(name string) ([]byte, error) {
+	key, err := ks.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(key, "", "  ")
+}
+
+// Get loads the key stored under name
+func (ks *Keystore) Get(name string) (*EncryptedKey, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	data, err := os.ReadFile(ks.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var key EncryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	return &key, nil
+}
+
+// FindByAgent returns the key belonging to agent
+func (ks *Keystore) FindByAgent(agent string) (*EncryptedKey, error) {
+	keys, err := ks.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.Agent == agent && key.Retired == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no key for agent %s", ErrKeyNotFound, agent)
+}
+
+// List returns all stored keys sorted by name
+func (ks *Keystore) List() ([]*EncryptedKey, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	keys := make([]*EncryptedKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		key, err := ks.Get(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			ks.logger.WithError(err).WithField("file", entry.Name()).Warn("Skipping unreadable key file")
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+	return keys, nil
+}
+
+// Retire marks the key stored under name as rotated out
+func (ks *Keystore) Retire(name string) error {
+	key, err := ks.Get(name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	key.Retired = &now
+	if err := ks.write(key); err != nil {
+		return err
+	}
+
+	ks.logger.WithField("name", name).Info("Retired agent key")
+	return nil
+}
+
+// Delete removes the key stored under name
+func (ks *Keystore) Delete(name string) error {
+	if _, err := ks.Get(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(ks.path(name)); err != nil {
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+
+	ks.logger.WithField("name", name).Info("Deleted agent key")
+	return nil
+}
+
+// Unlock decrypts the key with passphrase and checks that its name and
+// agent were not changed since it was encrypted
+func (k *EncryptedKey) Unlock(passphrase string) (*crypto.KeyPair, error) {
+	if k.Crypto.KDF != kdfScrypt {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKDF, k.Crypto.KDF)
+	}
+	params := k.Crypto.KDFParams
+	if params.N > maxScryptN || params.R > maxScryptR || params.P > maxScryptP {
+		return nil, fmt.Errorf("%w: n=%d, r=%d, p=%d", ErrKDFParams, params.N, params.R, params.P)
+	}
+
+	salt, err := hex.DecodeString(k.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	nonceBytes, err := hex.DecodeString(k.Crypto.Nonce)
+	if err != nil || len(nonceBytes) != 24 {
+		return nil, ErrWrongPassphrase
+	}
+	ciphertext, err := hex.DecodeString(k.Crypto.Ciphertext)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	mac, err := hex.DecodeString(k.Crypto.MAC)
+	if err != nil {
+		return nil, ErrTamperedKey
+	}
+
+	secret, macKey, err := deriveKey(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], nonceBytes)
+
+	seed, ok := secretbox.Open(nil, ciphertext, &nonce, secret)
+	if !ok {
+		return nil, ErrWrongPassphrase
+	}
+	if !hmac.Equal(mac, k.mac(macKey)) {
+		return nil, ErrTamperedKey
+	}
+
+	pair, err := crypto.NewKeyPairFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(pair.PublicKey()) != k.PublicKey {
+		return nil, ErrWrongPassphrase
+	}
+	return pair, nil
+}
+
+// mac returns the MAC of the key's name, agent and public key. Each field
+// is length-prefixed so that no two keys share an input.
+func (k *EncryptedKey) mac(macKey []byte) []byte {
+	h := hmac.New(sha256.New, macKey)
+	for _, field := range []string{k.Name, k.Agent, k.PublicKey} {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return h.Sum(nil)
+}
+
+func (ks *Keystore) path(name string) string {
+	return filepath.Join(ks.dir, name+".json")
+}
+
+func (ks *Keystore) write(key *EncryptedKey) error {
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal key: %w", err)
+	}
+
+	if err := os.WriteFile(ks.path(key.Name), data, 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+func encrypt(name, agent string, pair *crypto.KeyPair, passphrase string) (*EncryptedKey, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	params := KDFParams{
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+		Salt: hex.EncodeToString(salt),
+	}
+
+	secret, macKey, err := deriveKey(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &EncryptedKey{
+		Name:      name,
+		Agent:     agent,
+		PublicKey: hex.EncodeToString(pair.PublicKey()),
+		Crypto: Cipher{
+			KDF:        kdfScrypt,
+			KDFParams:  params,
+			Cipher:     cipherSecret,
+			Nonce:      hex.EncodeToString(nonce[:]),
+			Ciphertext: hex.EncodeToString(secretbox.Seal(nil, pair.Seed(), &nonce, secret)),
+		},
+	}
+	key.Crypto.MAC = hex.EncodeToString(key.mac(macKey))
+	return key, nil
+}
+
+// deriveKey derives the encryption key of the seed and the MAC key of the
+// key file's metadata from passphrase
+func deriveKey(passphrase string, salt []byte, params KDFParams) (*[32]byte, []byte, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	var secret [32]byte
+	copy(secret[:], derived[:32])
+	return &secret, derived[32:], nil
+}
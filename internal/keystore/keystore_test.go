@@ -0,0 +1,140 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeystore(t *testing.T) *Keystore {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	ks, err := New(t.TempDir(), logger)
+	assert.NoError(t, err)
+	return ks
+}
+
+func TestGenerateAndUnlock(t *testing.T) {
+	ks := newTestKeystore(t)
+
+	key, err := ks.Generate("alice", "agent:alice", "correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, "agent:alice", key.Agent)
+
+	stored, err := ks.Get("alice")
+	assert.NoError(t, err)
+	pair, err := stored.Unlock("correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, key.PublicKey, hex.EncodeToString(pair.PublicKey()))
+
+	_, err = stored.Unlock("battery staple")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	_, err = ks.Generate("alice", "agent:alice", "correct horse")
+	assert.ErrorIs(t, err, ErrKeyExists)
+	_, err = ks.Generate("../alice", "agent:alice", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidName)
+	_, err = ks.Generate("bob", "agent:bob", "")
+	assert.ErrorIs(t, err, ErrEmptyPassphrase)
+
+	// Without an agent, the key signs for its own did:key
+	own, err := ks.Generate("carol", "", "correct horse")
+	assert.NoError(t, err)
+	pair, err = own.Unlock("correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, did.FromPublicKey(pair.PublicKey()), own.Agent)
+}
+
+func TestExportImport(t *testing.T) {
+	source, target := newTestKeystore(t), newTestKeystore(t)
+
+	pair, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	_, err = source.Add("alice", "agent:alice", pair, "correct horse")
+	assert.NoError(t, err)
+
+	data, err := source.Export("alice")
+	assert.NoError(t, err)
+	imported, err := target.Import(data)
+	assert.NoError(t, err)
+	_, err = target.Import(data)
+	assert.ErrorIs(t, err, ErrKeyExists)
+
+	unlocked, err := imported.Unlock("correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, pair.PublicKey(), unlocked.PublicKey())
+
+	found, err := target.FindByAgent("agent:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", found.Name)
+}
+
+func TestUnlockRejectsTamperedKeys(t *testing.T) {
+	ks := newTestKeystore(t)
+	_, err := ks.Generate("alice", "agent:alice", "correct horse")
+	assert.NoError(t, err)
+	data, err := ks.Export("alice")
+	assert.NoError(t, err)
+
+	tamper := func(edit func(key *EncryptedKey)) *EncryptedKey {
+		var key EncryptedKey
+		assert.NoError(t, json.Unmarshal(data, &key))
+		edit(&key)
+		return &key
+	}
+
+	// A key file relabelled to sign for another agent
+	relabelled := tamper(func(key *EncryptedKey) { key.Agent = "agent:mallory" })
+	_, err = relabelled.Unlock("correct horse")
+	assert.ErrorIs(t, err, ErrTamperedKey)
+
+	renamed := tamper(func(key *EncryptedKey) { key.Name = "bob" })
+	_, err = renamed.Unlock("correct horse")
+	assert.ErrorIs(t, err, ErrTamperedKey)
+
+	// Parameters that would exhaust memory are refused before deriving
+	expensive := tamper(func(key *EncryptedKey) { key.Crypto.KDFParams.N = 1 << 30 })
+	_, err = expensive.Unlock("correct horse")
+	assert.ErrorIs(t, err, ErrKDFParams)
+
+	unknown := tamper(func(key *EncryptedKey) { key.Crypto.KDF = "pbkdf2" })
+	_, err = unknown.Unlock("correct horse")
+	assert.ErrorIs(t, err, ErrUnsupportedKDF)
+}
+
+func TestRetire(t *testing.T) {
+	ks := newTestKeystore(t)
+	_, err := ks.Generate("alice-1", "agent:alice", "correct horse")
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.Retire("alice-1"))
+	retired, err := ks.Get("alice-1")
+	assert.NoError(t, err)
+	assert.NotNil(t, retired.Retired)
+
+	// Retired keys still unlock but no longer sign for their agent
+	_, err = retired.Unlock("correct horse")
+	assert.NoError(t, err)
+	_, err = ks.FindByAgent("agent:alice")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	next, err := ks.Generate("alice-2", "agent:alice", "correct horse")
+	assert.NoError(t, err)
+	found, err := ks.FindByAgent("agent:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, next.Name, found.Name)
+
+	keys, err := ks.List()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	assert.NoError(t, ks.Delete("alice-1"))
+	assert.ErrorIs(t, ks.Retire("alice-1"), ErrKeyNotFound)
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"axia/internal/axiom"
+	"axia/internal/trust"
+	"github.com/sirupsen/logrus"
+)
+
+// maxClaimsBody limits the size of a claim submission
+const maxClaimsBody = 1 << 20
+
+// ClaimStore persists accepted claims, such as database.DB
+type ClaimStore interface {
+	StoreClaim(ctx context.Context, claim *axiom.Claim) error
+}
+
+// ClaimsHandler accepts signed claims, as JSON or compact JWTs, adds them
+// to the trust network and stores them
+type ClaimsHandler struct {
+	manager *axiom.Manager
+	network *trust.Network
+	store   ClaimStore
+	logger  *logrus.Logger
+}
+
+// NewClaimsHandler creates a handler for the claims API
+func NewClaimsHandler(manager *axiom.Manager, network *trust.Network, store ClaimStore, logger *logrus.Logger) *ClaimsHandler {
+	return &ClaimsHandler{
+		manager: manager,
+		network: network,
+		store:   store,
+		logger:  logger,
+	}
+}
+
+// ServeHTTP verifies every submitted claim before adding any of them
+func (h *ClaimsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := axiom.ReadClaims(io.LimitReader(r.Body, maxClaimsBody))
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to decode submitted claims")
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	if len(claims) == 0 {
+		http.Error(w, "No claims submitted", http.StatusBadRequest)
+		return
+	}
+
+	for i, claim := range claims {
+		if err := h.manager.VerifyClaim(r.Context(), claim); err != nil {
+			h.logger.WithError(err).WithField("issuer", claim.Issuer).Warn("Rejected submitted claim")
+			http.Error(w, fmt.Sprintf("claim %d failed verification: %v", i+1, err), http.StatusUnprocessableEntity)
+			return
+		}
+		if !axiom.ThresholdMet(claim) {
+			http.Error(w, fmt.Sprintf("claim %d: %v", i+1, axiom.ErrThresholdNotMet), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	// Claims are stored as the network accepted them, with their timestamp
+	for _, claim := range claims {
+		if err := h.network.AddClaim(claim); err != nil {
+			h.logger.WithError(err).Error("Failed to add claim")
+			http.Error(w, "Failed to add claim", http.StatusInternalServerError)
+			return
+		}
+		if err := h.store.StoreClaim(r.Context(), claim); err != nil {
+			h.logger.WithError(err).Error("Failed to store claim")
+			http.Error(w, "Failed to store claim", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]int{"accepted": len(claims)})
+}
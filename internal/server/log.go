@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"axia/internal/translog"
+	"github.com/sirupsen/logrus"
+)
+
+// LogHandler serves signed tree heads and proofs from the transparency log
+type LogHandler struct {
+	log    *translog.Log
+	logger *logrus.Logger
+}
+
+// NewLogHandler creates a handler for the transparency log API
+func NewLogHandler(log *translog.Log, logger *logrus.Logger) *LogHandler {
+	return &LogHandler{
+		log:    log,
+		logger: logger,
+	}
+}
+
+// Register adds the log endpoints to mux. They are read-only and public,
+// since anyone must be able to audit the log.
+func (h *LogHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/api/log/head", h.handleHead)
+	mux.HandleFunc("/api/log/proof", h.handleProof)
+	mux.HandleFunc("/api/log/consistency", h.handleConsistency)
+}
+
+func (h *LogHandler) handleHead(w http.ResponseWriter, r *http.Request) {
+	sth, err := h.log.SignedHead()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to sign tree head")
+		http.Error(w, "Failed to sign tree head", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, sth)
+}
+
+// handleProof serves inclusion proofs: ?leaf_hash=<hex or base64>&tree_size=<n>
+func (h *LogHandler) handleProof(w http.ResponseWriter, r *http.Request) {
+	leafHash, err := decodeHash(r.URL.Query().Get("leaf_hash"))
+	if err != nil {
+		http.Error(w, "Invalid leaf_hash", http.StatusBadRequest)
+		return
+	}
+	size, err := queryUint(r, "tree_size")
+	if err != nil {
+		http.Error(w, "Invalid tree_size", http.StatusBadRequest)
+		return
+	}
+
+	proof, err := h.log.ProveInclusion(leafHash, size)
+	if errors.Is(err, translog.ErrLeafNotFound) {
+		http.Error(w, "Leaf not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+// handleConsistency serves consistency proofs: ?first=<n>&second=<n>
+func (h *LogHandler) handleConsistency(w http.ResponseWriter, r *http.Request) {
+	first, err := queryUint(r, "first")
+	if err != nil {
+		http.Error(w, "Invalid first", http.StatusBadRequest)
+		return
+	}
+	second, err := queryUint(r, "second")
+	if err != nil {
+		http.Error(w, "Invalid second", http.StatusBadRequest)
+		return
+	}
+
+	proof, err := h.log.ProveConsistency(first, second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+func queryUint(r *http.Request, name string) (uint64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func decodeHash(s string) ([]byte, error) {
+	if hash, err := hex.DecodeString(s); err == nil && len(hash) == 32 {
+		return hash, nil
+	}
+	hash, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(hash) != 32 {
+		return nil, errors.New("leaf hash must be 32 bytes in hex or base64")
+	}
+	return hash, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -9,6 +9,9 @@ import (
 	"axia/internal/trust"
 	"axia/internal/social/twitter"
 	"axia/internal/auth"
+	"axia/internal/crypto"
+	"axia/internal/translog"
+	"axia/internal/timestamp"
 )
 
 type Server struct {
@@ -18,7 +21,7 @@ type Server struct {
 	auth    *auth.Authenticator
 }
 
-func NewServer(port int, manager *axiom.Manager, network *trust.Network, logger *logrus.Logger) (*Server, error) {
+func NewServer(port int, manager *axiom.Manager, network *trust.Network, store ClaimStore, log *translog.Log, tsa *timestamp.Authority, signer crypto.Signer, logger *logrus.Logger) (*Server, error) {
 	auth, err := auth.NewAuthenticator(logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize authenticator: %w", err)
@@ -30,6 +33,14 @@ func NewServer(port int, manager *axiom.Manager, network *trust.Network, logger
 	
 	// Wrap handlers with authentication middleware
 	mux.Handle("/webhook/twitter", auth.Middleware(http.HandlerFunc(twitterHandler.HandleWebhook)))
+	mux.Handle("/api/claims", auth.Middleware(NewClaimsHandler(manager, network, store, logger)))
+	NewLogHandler(log, logger).Register(mux)
+	mux.Handle("/api/status/revocation", NewStatusHandler(network, signer, logger))
+
+	// Issuing tokens extends the authority's chain, auditing it is public
+	tsaHandler := NewTimestampHandler(tsa, logger)
+	mux.Handle("/api/timestamp", auth.Middleware(tsaHandler))
+	mux.HandleFunc("/api/timestamp/tokens", tsaHandler.handleTokens)
 
 	return &Server{
 		server: &http.Server{
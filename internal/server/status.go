@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/status"
+	"axia/internal/trust"
+	"github.com/sirupsen/logrus"
+)
+
+// StatusHandler publishes the network's revocation status list as a signed
+// Bitstring Status List credential
+type StatusHandler struct {
+	network *trust.Network
+	signer  crypto.Signer
+	logger  *logrus.Logger
+}
+
+// NewStatusHandler creates a handler that signs the status list with signer
+func NewStatusHandler(network *trust.Network, signer crypto.Signer, logger *logrus.Logger) *StatusHandler {
+	return &StatusHandler{
+		network: network,
+		signer:  signer,
+		logger:  logger,
+	}
+}
+
+// ServeHTTP serves the current list; its id is the URL it was fetched from
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	scheme := "https"
+	if r.TLS == nil {
+		scheme = "http"
+	}
+	id := scheme + "://" + r.Host + r.URL.Path
+
+	cred, err := status.Issue(h.network.StatusList(), id, h.signer, time.Now())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to issue status list")
+		http.Error(w, "Failed to issue status list", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, cred)
+}
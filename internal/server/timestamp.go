@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"axia/internal/timestamp"
+	"github.com/sirupsen/logrus"
+)
+
+// maxTokenRange limits the number of tokens served per request
+const maxTokenRange = 1000
+
+// TimestampHandler serves the node's timestamp authority
+type TimestampHandler struct {
+	authority *timestamp.Authority
+	logger    *logrus.Logger
+}
+
+// NewTimestampHandler creates a handler for the timestamp API
+func NewTimestampHandler(authority *timestamp.Authority, logger *logrus.Logger) *TimestampHandler {
+	return &TimestampHandler{
+		authority: authority,
+		logger:    logger,
+	}
+}
+
+// timestampRequest asks for a token over a message imprint, like an RFC
+// 3161 TimeStampReq
+type timestampRequest struct {
+	Imprint string `json:"messageImprint"`
+}
+
+// ServeHTTP issues a token for the imprint in a POST body
+func (h *TimestampHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req timestampRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 4096)).Decode(&req); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.authority.Stamp(r.Context(), req.Imprint)
+	if errors.Is(err, timestamp.ErrInvalidImprint) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to issue timestamp token")
+		http.Error(w, "Failed to issue timestamp token", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, token)
+}
+
+// handleTokens serves issued tokens for auditing the chain:
+// ?from=<serial>&to=<serial>
+func (h *TimestampHandler) handleTokens(w http.ResponseWriter, r *http.Request) {
+	from, err := queryUint(r, "from")
+	if err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return
+	}
+	to, err := queryUint(r, "to")
+	if err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return
+	}
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 || (to >= from && to-from >= maxTokenRange) {
+		to = from + maxTokenRange - 1
+	}
+
+	tokens, err := h.authority.Tokens(r.Context(), from, to)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load timestamp tokens")
+		http.Error(w, "Failed to load timestamp tokens", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, tokens)
+}
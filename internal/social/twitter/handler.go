@@ -91,6 +91,10 @@ func (h *Handler) processTweet(tweet TweetData) error {
 		return fmt.Errorf("failed to create claim: %w", err)
 	}
 
+	if err := axiom.VerifyProof(claim); err != nil {
+		return fmt.Errorf("claim failed verification: %w", err)
+	}
+
 	// Add claim to trust network
 	if err := h.network.AddClaim(claim); err != nil {
 		return fmt.Errorf("failed to add claim to network: %w", err)
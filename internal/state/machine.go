@@ -1,6 +1,8 @@
 package state
 
 import (
+	"context"
+
 	"github.com/looplab/fsm"
 	"github.com/sirupsen/logrus"
 )
@@ -18,6 +20,8 @@ const (
 	StateProcessing = "processing"
 	StateCompleted  = "completed"
 	StateError      = "error"
+	// StateDisputed marks an element that conflicting claims are made about
+	StateDisputed = "disputed"
 )
 
 // NewNodeStateManager creates a new state manager for nodes
@@ -30,16 +34,18 @@ func NewNodeStateManager(logger *logrus.Logger) *StateManager {
 				{Name: "process", Src: []string{StateValidated}, Dst: StateProcessing},
 				{Name: "complete", Src: []string{StateProcessing}, Dst: StateCompleted},
 				{Name: "error", Src: []string{"*"}, Dst: StateError},
+				{Name: "dispute", Src: []string{StateInitial, StateValidated, StateProcessing, StateCompleted}, Dst: StateDisputed},
+				{Name: "resolve", Src: []string{StateDisputed}, Dst: StateValidated},
 			},
 			fsm.Callbacks{
-				"before_event": func(e *fsm.Event) {
+				"before_event": func(_ context.Context, e *fsm.Event) {
 					logger.WithFields(logrus.Fields{
 						"from":  e.Src,
 						"to":    e.Dst,
 						"event": e.Event,
 					}).Info("State transition starting")
 				},
-				"after_event": func(e *fsm.Event) {
+				"after_event": func(_ context.Context, e *fsm.Event) {
 					logger.WithFields(logrus.Fields{
 						"from":  e.Src,
 						"to":    e.Dst,
@@ -50,4 +56,21 @@ func NewNodeStateManager(logger *logrus.Logger) *StateManager {
 		),
 		log: logger,
 	}
-} 
\ No newline at end of file
+} 
+
+// Dispute moves the element to StateDisputed
+func (s *StateManager) Dispute(ctx context.Context) error {
+	if s.FSM.Current() == StateDisputed {
+		return nil
+	}
+	return s.FSM.Event(ctx, "dispute")
+}
+
+// Resolve moves a disputed element to StateValidated once the claims about
+// it no longer conflict
+func (s *StateManager) Resolve(ctx context.Context) error {
+	if s.FSM.Current() != StateDisputed {
+		return nil
+	}
+	return s.FSM.Event(ctx, "resolve")
+}
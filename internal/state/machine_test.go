@@ -0,0 +1,31 @@
+package state
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisputeAndResolve(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	manager := NewNodeStateManager(logger)
+	assert.NoError(t, manager.FSM.Event(ctx, "validate"))
+
+	// Resolving an undisputed element leaves it alone
+	assert.NoError(t, manager.Resolve(ctx))
+	assert.Equal(t, StateValidated, manager.FSM.Current())
+
+	assert.NoError(t, manager.Dispute(ctx))
+	assert.Equal(t, StateDisputed, manager.FSM.Current())
+	assert.NoError(t, manager.Dispute(ctx))
+	assert.Equal(t, StateDisputed, manager.FSM.Current())
+
+	assert.NoError(t, manager.Resolve(ctx))
+	assert.Equal(t, StateValidated, manager.FSM.Current())
+}
@@ -0,0 +1,96 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"axia/internal/vc"
+)
+
+const (
+	// TypeStatusListCredential is the credential type of published lists
+	TypeStatusListCredential = "BitstringStatusListCredential"
+
+	// TypeStatusList is the type of the list credential's subject
+	TypeStatusList = "BitstringStatusList"
+
+	// PurposeRevocation marks entries as permanently revoked
+	PurposeRevocation = "revocation"
+)
+
+var ErrNotStatusList = errors.New("credential is not a revocation status list")
+
+// Credential is a signed Bitstring Status List credential
+type Credential struct {
+	Context           []string  `json:"@context"`
+	ID                string    `json:"id"`
+	Type              []string  `json:"type"`
+	Issuer            string    `json:"issuer"`
+	ValidFrom         string    `json:"validFrom"`
+	CredentialSubject Subject   `json:"credentialSubject"`
+	Proof             *vc.Proof `json:"proof,omitempty"`
+}
+
+// Subject carries the encoded list
+type Subject struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	StatusPurpose string `json:"statusPurpose"`
+	EncodedList   string `json:"encodedList"`
+}
+
+// Issue publishes list as a revocation status list credential identified
+// by id and signed by signer with an eddsa-jcs-2022 proof
+func Issue(list *List, id string, signer crypto.Signer, now time.Time) (*Credential, error) {
+	encoded, err := list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &Credential{
+		Context:   []string{vc.ContextV2},
+		ID:        id,
+		Type:      []string{vc.TypeCredential, TypeStatusListCredential},
+		Issuer:    did.FromPublicKey(signer.PublicKey()),
+		ValidFrom: now.UTC().Format(time.RFC3339),
+		CredentialSubject: Subject{
+			ID:            id + "#list",
+			Type:          TypeStatusList,
+			StatusPurpose: PurposeRevocation,
+			EncodedList:   encoded,
+		},
+	}
+
+	proof, err := vc.SignDocument(cred, cred.Context, signer, now)
+	if err != nil {
+		return nil, err
+	}
+	cred.Proof = proof
+	return cred, nil
+}
+
+// Verify checks the credential's proof and that it was signed by its issuer
+func (c *Credential) Verify() error {
+	if len(c.Type) != 2 || c.Type[1] != TypeStatusListCredential ||
+		c.CredentialSubject.StatusPurpose != PurposeRevocation {
+		return ErrNotStatusList
+	}
+	if c.Proof == nil {
+		return vc.ErrUnsigned
+	}
+	if c.Proof.VerificationMethod != did.VerificationMethodID(c.Issuer) {
+		return fmt.Errorf("%w: list is not signed by its issuer", vc.ErrInvalidProof)
+	}
+
+	unsecured := *c
+	unsecured.Proof = nil
+	return vc.VerifyDocument(&unsecured, c.Context, c.Proof)
+}
+
+// List decodes the credential's status list
+func (c *Credential) List() (*List, error) {
+	return Decode(c.CredentialSubject.EncodedList)
+}
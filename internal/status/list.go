@@ -0,0 +1,104 @@
+package status
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"axia/internal/crypto"
+)
+
+// MinListSize is the minimum number of entries in a status list, which
+// keeps individual entries from being correlated by list size
+const MinListSize = 131072
+
+var (
+	ErrIndexOutOfRange = errors.New("status index out of range")
+	ErrInvalidList     = errors.New("invalid encoded status list")
+)
+
+// List is a bitstring of status entries. Entry 0 is the most significant
+// bit of the first byte.
+type List struct {
+	bits []byte
+}
+
+// NewList creates a list of at least size entries, all unset
+func NewList(size uint64) *List {
+	if size < MinListSize {
+		size = MinListSize
+	}
+	return &List{bits: make([]byte, (size+7)/8)}
+}
+
+// Len returns the number of entries in the list
+func (l *List) Len() uint64 {
+	return uint64(len(l.bits)) * 8
+}
+
+// Set sets or clears the entry at index, growing the list as needed
+func (l *List) Set(index uint64, value bool) {
+	if index >= l.Len() {
+		grown := make([]byte, (index/8+1)*2)
+		copy(grown, l.bits)
+		l.bits = grown
+	}
+	mask := byte(0x80) >> (index % 8)
+	if value {
+		l.bits[index/8] |= mask
+	} else {
+		l.bits[index/8] &^= mask
+	}
+}
+
+// Clone returns a copy of the list
+func (l *List) Clone() *List {
+	return &List{bits: append([]byte(nil), l.bits...)}
+}
+
+// Get returns the entry at index
+func (l *List) Get(index uint64) (bool, error) {
+	if index >= l.Len() {
+		return false, ErrIndexOutOfRange
+	}
+	return l.bits[index/8]&(byte(0x80)>>(index%8)) != 0, nil
+}
+
+// Encode returns the list as a GZIP-compressed, base64url multibase string
+func (l *List) Encode() (string, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(l.bits); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	return crypto.EncodeMultibaseBase64URL(buf.Bytes()), nil
+}
+
+// Decode parses a list produced by Encode
+func Decode(encoded string) (*List, error) {
+	if len(encoded) == 0 || encoded[0] != crypto.MultibaseBase64URL {
+		return nil, ErrInvalidList
+	}
+	compressed, err := crypto.DecodeMultibase(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidList, err)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidList, err)
+	}
+	bits, err := io.ReadAll(io.LimitReader(r, 1<<24))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidList, err)
+	}
+	if len(bits)*8 < MinListSize {
+		return nil, fmt.Errorf("%w: list is shorter than %d entries", ErrInvalidList, MinListSize)
+	}
+	return &List{bits: bits}, nil
+}
@@ -0,0 +1,66 @@
+package status
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"axia/internal/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRoundTrip(t *testing.T) {
+	list := NewList(0)
+	assert.Equal(t, uint64(MinListSize), list.Len())
+
+	list.Set(0, true)
+	list.Set(42, true)
+	list.Set(MinListSize+5, true)
+	assert.Greater(t, list.Len(), uint64(MinListSize+5))
+
+	encoded, err := list.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, byte('u'), encoded[0])
+
+	decoded, err := Decode(encoded)
+	assert.NoError(t, err)
+	for _, index := range []uint64{0, 42, MinListSize + 5} {
+		revoked, err := decoded.Get(index)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	}
+	revoked, err := decoded.Get(1)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Entry 0 is the most significant bit of the first byte
+	assert.Equal(t, byte(0x80), decoded.bits[0])
+}
+
+func TestCredential(t *testing.T) {
+	keyPair, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	list := NewList(0)
+	list.Set(7, true)
+
+	cred, err := Issue(list, "https://node.example/api/status/revocation", keyPair, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, cred.Verify())
+
+	data, err := json.Marshal(cred)
+	assert.NoError(t, err)
+	var parsed Credential
+	assert.NoError(t, json.Unmarshal(data, &parsed))
+	assert.NoError(t, parsed.Verify())
+
+	decoded, err := parsed.List()
+	assert.NoError(t, err)
+	revoked, _ := decoded.Get(7)
+	assert.True(t, revoked)
+
+	list.Set(7, false)
+	parsed.CredentialSubject.EncodedList, err = list.Encode()
+	assert.NoError(t, err)
+	assert.Error(t, parsed.Verify())
+}
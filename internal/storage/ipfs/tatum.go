@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"time"
 
+	"axia/internal/crypto"
 	"github.com/sirupsen/logrus"
 	"github.com/your-project/auth"
 )
@@ -55,7 +56,10 @@ func (t *TatumClient) UploadGraph(ctx context.Context, data interface{}) (string
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", t.apiKey)
 
-	t.logger.WithField("size", len(jsonData)).Info("Uploading graph to IPFS")
+	t.logger.WithFields(logrus.Fields{
+		"size": len(jsonData),
+		"cid":  crypto.NewCID(jsonData).String(),
+	}).Info("Uploading graph to IPFS")
 
 	resp, err := t.client.Do(req)
 	if err != nil {
@@ -73,6 +77,10 @@ func (t *TatumClient) UploadGraph(ctx context.Context, data interface{}) (string
 		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if err := verifyContent(uploadResp.IPFSID, jsonData); err != nil {
+		return "", err
+	}
+
 	t.logger.WithField("ipfs_id", uploadResp.IPFSID).Info("Successfully uploaded graph to IPFS")
 	return uploadResp.IPFSID, nil
 }
@@ -106,5 +114,23 @@ func (t *TatumClient) GetGraph(ctx context.Context, ipfsID string) ([]byte, erro
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if err := verifyContent(ipfsID, data); err != nil {
+		return nil, err
+	}
+
 	return data, nil
+}
+
+// verifyContent checks data against its IPFS ID when the ID is a raw-block
+// CID. Content stored as dag-pb nodes cannot be checked without rebuilding
+// the nodes, so other IDs are accepted as is.
+func verifyContent(ipfsID string, data []byte) error {
+	cid, err := crypto.ParseCID(ipfsID)
+	if err != nil || cid.Codec != crypto.CodecRaw {
+		return nil
+	}
+	if err := cid.Verify(data); err != nil {
+		return fmt.Errorf("IPFS content %s: %w", ipfsID, err)
+	}
+	return nil
 } 
\ No newline at end of file
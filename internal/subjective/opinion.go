@@ -0,0 +1,214 @@
+// Package subjective implements binomial opinions of subjective logic
+// (Jøsang, 2016). Unlike a bare probability, an opinion tells a lack of
+// evidence (uncertainty) apart from conflicting evidence (belief and
+// disbelief both high).
+package subjective
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultBaseRate is the prior probability used without other knowledge
+const DefaultBaseRate = 0.5
+
+// PriorWeight is the non-informative prior weight W mapping evidence to
+// opinions
+const PriorWeight = 2
+
+// epsilon absorbs rounding in opinions that should sum to 1
+const epsilon = 1e-9
+
+// Fusion operators
+const (
+	FusionCumulative = "cumulative"
+	FusionAveraging  = "averaging"
+)
+
+var (
+	ErrInvalidOpinion = errors.New("invalid opinion")
+	ErrUnknownFusion  = errors.New("unknown fusion operator")
+)
+
+// Opinion is a binomial opinion about a proposition. Belief, Disbelief and
+// Uncertainty sum to 1; BaseRate is the prior probability of the
+// proposition, which uncertainty defers to.
+type Opinion struct {
+	Belief      float64 `json:"belief"`
+	Disbelief   float64 `json:"disbelief"`
+	Uncertainty float64 `json:"uncertainty"`
+	BaseRate    float64 `json:"baseRate"`
+}
+
+// New returns a validated opinion
+func New(belief, disbelief, uncertainty, baseRate float64) (Opinion, error) {
+	o := Opinion{
+		Belief:      belief,
+		Disbelief:   disbelief,
+		Uncertainty: uncertainty,
+		BaseRate:    baseRate,
+	}
+	if err := o.Validate(); err != nil {
+		return Opinion{}, err
+	}
+	return o, nil
+}
+
+// Vacuous returns the opinion of total ignorance
+func Vacuous(baseRate float64) Opinion {
+	return Opinion{Uncertainty: 1, BaseRate: baseRate}
+}
+
+// Dogmatic returns the opinion without uncertainty that the proposition
+// holds with probability p
+func Dogmatic(p float64) Opinion {
+	return Opinion{Belief: p, Disbelief: 1 - p, BaseRate: DefaultBaseRate}
+}
+
+// FromEvidence maps positive and negative evidence to an opinion: the more
+// evidence, the less uncertainty
+func FromEvidence(positive, negative, baseRate float64) Opinion {
+	total := positive + negative + PriorWeight
+	return Opinion{
+		Belief:      positive / total,
+		Disbelief:   negative / total,
+		Uncertainty: PriorWeight / total,
+		BaseRate:    baseRate,
+	}
+}
+
+// FromConfidence maps a confidence in 0..1 backed by the given amount of
+// evidence to an opinion
+func FromConfidence(confidence, evidence float64) Opinion {
+	confidence = math.Max(0, math.Min(1, confidence))
+	return FromEvidence(confidence*evidence, (1-confidence)*evidence, DefaultBaseRate)
+}
+
+// Validate checks that the opinion's masses lie in 0..1 and sum to 1
+func (o Opinion) Validate() error {
+	for _, value := range []float64{o.Belief, o.Disbelief, o.Uncertainty, o.BaseRate} {
+		if math.IsNaN(value) || value < -epsilon || value > 1+epsilon {
+			return fmt.Errorf("%w: %v is outside 0..1", ErrInvalidOpinion, value)
+		}
+	}
+	if sum := o.Belief + o.Disbelief + o.Uncertainty; math.Abs(sum-1) > epsilon {
+		return fmt.Errorf("%w: belief, disbelief and uncertainty sum to %v", ErrInvalidOpinion, sum)
+	}
+	return nil
+}
+
+// Expectation returns the projected probability b + a·u
+func (o Opinion) Expectation() float64 {
+	return o.Belief + o.BaseRate*o.Uncertainty
+}
+
+// Discount returns the opinion about x held through o, the trust in x's
+// source. Distrust and uncertainty about the source both turn into
+// uncertainty.
+func (o Opinion) Discount(x Opinion) Opinion {
+	return Opinion{
+		Belief:      o.Belief * x.Belief,
+		Disbelief:   o.Belief * x.Disbelief,
+		Uncertainty: o.Disbelief + o.Uncertainty + o.Belief*x.Uncertainty,
+		BaseRate:    x.BaseRate,
+	}
+}
+
+// Fuse combines opinions with the named fusion operator
+func Fuse(fusion string, opinions ...Opinion) (Opinion, error) {
+	switch fusion {
+	case FusionCumulative:
+		return CumulativeFuse(opinions...), nil
+	case FusionAveraging:
+		return AveragingFuse(opinions...), nil
+	default:
+		return Opinion{}, fmt.Errorf("%w: %s", ErrUnknownFusion, fusion)
+	}
+}
+
+// CumulativeFuse combines opinions based on independent evidence, adding
+// up their evidence. Without opinions it returns the vacuous opinion.
+func CumulativeFuse(opinions ...Opinion) Opinion {
+	if len(opinions) == 0 {
+		return Vacuous(DefaultBaseRate)
+	}
+	fused := opinions[0]
+	for _, o := range opinions[1:] {
+		fused = cumulative(fused, o)
+	}
+	return fused
+}
+
+func cumulative(x, y Opinion) Opinion {
+	// Dogmatic opinions carry infinite evidence and are averaged
+	if x.Uncertainty < epsilon && y.Uncertainty < epsilon {
+		return Opinion{
+			Belief:    (x.Belief + y.Belief) / 2,
+			Disbelief: (x.Disbelief + y.Disbelief) / 2,
+			BaseRate:  (x.BaseRate + y.BaseRate) / 2,
+		}
+	}
+
+	k := x.Uncertainty + y.Uncertainty - x.Uncertainty*y.Uncertainty
+	fused := Opinion{
+		Belief:      (x.Belief*y.Uncertainty + y.Belief*x.Uncertainty) / k,
+		Disbelief:   (x.Disbelief*y.Uncertainty + y.Disbelief*x.Uncertainty) / k,
+		Uncertainty: x.Uncertainty * y.Uncertainty / k,
+		BaseRate:    (x.BaseRate + y.BaseRate) / 2,
+	}
+	if denominator := x.Uncertainty + y.Uncertainty - 2*x.Uncertainty*y.Uncertainty; denominator > epsilon {
+		fused.BaseRate = (x.BaseRate*y.Uncertainty + y.BaseRate*x.Uncertainty -
+			(x.BaseRate+y.BaseRate)*x.Uncertainty*y.Uncertainty) / denominator
+	}
+	return fused
+}
+
+// AveragingFuse combines opinions based on dependent evidence, such as
+// sources that observed the same events, averaging their evidence.
+// Without opinions it returns the vacuous opinion.
+func AveragingFuse(opinions ...Opinion) Opinion {
+	if len(opinions) == 0 {
+		return Vacuous(DefaultBaseRate)
+	}
+
+	// Dogmatic opinions outweigh all others and are averaged on their own
+	dogmatic := make([]Opinion, 0)
+	for _, o := range opinions {
+		if o.Uncertainty < epsilon {
+			dogmatic = append(dogmatic, o)
+		}
+	}
+	if len(dogmatic) > 0 {
+		fused := Opinion{}
+		for _, o := range dogmatic {
+			fused.Belief += o.Belief / float64(len(dogmatic))
+			fused.Disbelief += o.Disbelief / float64(len(dogmatic))
+			fused.BaseRate += o.BaseRate / float64(len(dogmatic))
+		}
+		return fused
+	}
+
+	// Each opinion is weighed by the product of the others' uncertainty
+	fused := Opinion{}
+	var total, product float64 = 0, 1
+	for i, o := range opinions {
+		weight := 1.0
+		for j, other := range opinions {
+			if i != j {
+				weight *= other.Uncertainty
+			}
+		}
+		fused.Belief += o.Belief * weight
+		fused.Disbelief += o.Disbelief * weight
+		total += weight
+		product *= o.Uncertainty
+	}
+	fused.Belief /= total
+	fused.Disbelief /= total
+	fused.Uncertainty = float64(len(opinions)) * product / total
+	for _, o := range opinions {
+		fused.BaseRate += o.BaseRate / float64(len(opinions))
+	}
+	return fused
+}
@@ -0,0 +1,43 @@
+package subjective
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpinions(t *testing.T) {
+	_, err := New(0.5, 0.6, 0, DefaultBaseRate)
+	assert.ErrorIs(t, err, ErrInvalidOpinion)
+
+	// No evidence is uncertain; conflicting evidence is not
+	ignorant := FromEvidence(0, 0, DefaultBaseRate)
+	conflicted := FromEvidence(50, 50, DefaultBaseRate)
+	assert.Equal(t, Vacuous(DefaultBaseRate), ignorant)
+	assert.InDelta(t, ignorant.Expectation(), conflicted.Expectation(), 1e-9)
+	assert.Less(t, conflicted.Uncertainty, 0.02)
+
+	// Trust turns into uncertainty as it weakens
+	opinion := FromConfidence(0.9, 8)
+	assert.NoError(t, opinion.Validate())
+	full := Opinion{Belief: 1}.Discount(opinion)
+	assert.InDelta(t, opinion.Belief, full.Belief, 1e-9)
+	weak := FromConfidence(0.3, 8).Discount(opinion)
+	assert.NoError(t, weak.Validate())
+	assert.Greater(t, weak.Uncertainty, opinion.Uncertainty)
+
+	// Cumulative fusion adds up evidence, averaging fusion does not
+	cumulative := CumulativeFuse(opinion, opinion)
+	assert.NoError(t, cumulative.Validate())
+	expected := FromConfidence(0.9, 16)
+	assert.InDelta(t, expected.Belief, cumulative.Belief, 1e-9)
+	assert.InDelta(t, expected.Uncertainty, cumulative.Uncertainty, 1e-9)
+
+	averaged, err := Fuse(FusionAveraging, opinion, opinion, opinion)
+	assert.NoError(t, err)
+	assert.InDelta(t, opinion.Belief, averaged.Belief, 1e-9)
+	assert.InDelta(t, opinion.Uncertainty, averaged.Uncertainty, 1e-9)
+
+	_, err = Fuse("majority", opinion)
+	assert.ErrorIs(t, err, ErrUnknownFusion)
+}
@@ -0,0 +1,314 @@
+package timestamp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"axia/internal/canon"
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/sirupsen/logrus"
+)
+
+// TokenVersion is the version of the tokens issued by Authority
+const TokenVersion = 1
+
+// tokenDomain separates token signatures from other data signed by the
+// same key
+const tokenDomain = "axia-timestamp-v1"
+
+var (
+	ErrInvalidToken    = errors.New("invalid timestamp token")
+	ErrInvalidImprint  = errors.New("invalid message imprint")
+	ErrImprintMismatch = errors.New("timestamp token does not cover the data")
+	ErrBrokenChain     = errors.New("timestamp tokens do not form a chain")
+)
+
+// Token states that the authority saw the message imprint no later than
+// Time, in the style of an RFC 3161 TSTInfo. Each token names the hash of
+// the one issued before it, so an authority cannot issue a backdated token
+// without forking its own chain.
+type Token struct {
+	Version int `json:"version"`
+	// Authority is the did:key of the signing authority
+	Authority string    `json:"authority"`
+	Serial    uint64    `json:"serial"`
+	Time      time.Time `json:"genTime"`
+	// Imprint is the multibase multihash of the stamped data
+	Imprint   string `json:"messageImprint"`
+	Previous  string `json:"previous,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// Storage persists the tokens issued by an authority. AppendToken must fail
+// if the token's serial is already taken.
+type Storage interface {
+	LastToken(ctx context.Context) (*Token, error)
+	AppendToken(ctx context.Context, token *Token) error
+	Tokens(ctx context.Context, from, to uint64) ([]*Token, error)
+}
+
+// Authority issues hash-chained timestamp tokens
+type Authority struct {
+	mu      sync.Mutex
+	storage Storage
+	signer  crypto.Signer
+	id      string
+	last    *Token
+	logger  *logrus.Logger
+}
+
+// NewAuthority opens the authority whose tokens are held by storage,
+// resuming its chain after the last stored token
+func NewAuthority(ctx context.Context, storage Storage, signer crypto.Signer, logger *logrus.Logger) (*Authority, error) {
+	last, err := storage.LastToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last timestamp token: %w", err)
+	}
+
+	return &Authority{
+		storage: storage,
+		signer:  signer,
+		id:      did.FromPublicKey(signer.PublicKey()),
+		last:    last,
+		logger:  logger,
+	}, nil
+}
+
+// ID returns the did:key of the authority
+func (a *Authority) ID() string {
+	return a.id
+}
+
+// Stamp issues a token for a message imprint
+func (a *Authority) Stamp(ctx context.Context, imprint string) (*Token, error) {
+	if _, err := imprintAlgorithm(imprint); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	token := &Token{
+		Version:   TokenVersion,
+		Authority: a.id,
+		Serial:    1,
+		Time:      time.Now().UTC().Truncate(time.Millisecond),
+		Imprint:   imprint,
+	}
+	if a.last != nil {
+		previous, err := a.last.Hash()
+		if err != nil {
+			return nil, err
+		}
+		token.Serial = a.last.Serial + 1
+		token.Previous = previous
+		// Token times never run backwards along the chain
+		if token.Time.Before(a.last.Time) {
+			token.Time = a.last.Time
+		}
+	}
+
+	data, err := token.signedData()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := a.signer.Sign(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign timestamp token: %w", err)
+	}
+	token.Signature = crypto.EncodeMultibase(signature)
+
+	if err := a.storage.AppendToken(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to store timestamp token: %w", err)
+	}
+	a.last = token
+
+	a.logger.WithFields(logrus.Fields{
+		"serial":  token.Serial,
+		"imprint": imprint,
+	}).Debug("Issued timestamp token")
+	return token, nil
+}
+
+// Tokens returns the issued tokens with serials from to to, inclusive, so
+// auditors can check the chain
+func (a *Authority) Tokens(ctx context.Context, from, to uint64) ([]*Token, error) {
+	return a.storage.Tokens(ctx, from, to)
+}
+
+// Imprint returns the message imprint of data, hashed with the default
+// hash algorithm
+func Imprint(data []byte) (string, error) {
+	algorithm, err := crypto.LookupHash(crypto.NewProofGenerator().HashAlgorithm())
+	if err != nil {
+		return "", err
+	}
+	return crypto.EncodeMultibase(algorithm.Sum(data)), nil
+}
+
+// Covers reports whether the token's imprint is the imprint of data
+func (t *Token) Covers(data []byte) (bool, error) {
+	algorithm, err := imprintAlgorithm(t.Imprint)
+	if err != nil {
+		return false, err
+	}
+	return crypto.EncodeMultibase(algorithm.Sum(data)) == t.Imprint, nil
+}
+
+// Verify checks the token's signature against its authority's key
+func (t *Token) Verify() error {
+	if t.Version != TokenVersion {
+		return fmt.Errorf("%w: version %d", ErrInvalidToken, t.Version)
+	}
+	publicKey, err := did.PublicKeyFromDIDKey(t.Authority)
+	if err != nil {
+		return fmt.Errorf("%w: authority: %v", ErrInvalidToken, err)
+	}
+	signature, err := crypto.DecodeMultibase(t.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+
+	data, err := t.signedData()
+	if err != nil {
+		return err
+	}
+	if err := crypto.Verify(publicKey, data, signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+// Hash returns the hash of the signed token that the next token in the
+// chain names as previous
+func (t *Token) Hash() (string, error) {
+	algorithm, err := crypto.LookupHash(crypto.NewProofGenerator().HashAlgorithm())
+	if err != nil {
+		return "", err
+	}
+	return t.hash(algorithm)
+}
+
+func (t *Token) hash(algorithm *crypto.HashAlgorithm) (string, error) {
+	data, err := canon.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return crypto.EncodeMultibase(algorithm.Sum(data)), nil
+}
+
+// Follows reports whether t is the token issued right after previous
+func (t *Token) Follows(previous *Token) (bool, error) {
+	if t.Authority != previous.Authority || t.Serial != previous.Serial+1 {
+		return false, nil
+	}
+	algorithm, err := imprintAlgorithm(t.Previous)
+	if err != nil {
+		return false, fmt.Errorf("%w: previous: %v", ErrInvalidToken, err)
+	}
+	hash, err := previous.hash(algorithm)
+	if err != nil {
+		return false, err
+	}
+	return hash == t.Previous && !t.Time.Before(previous.Time), nil
+}
+
+// VerifyChain checks that tokens are validly signed, consecutive tokens of
+// one authority, each naming the hash of the one before it
+func VerifyChain(tokens []*Token) error {
+	for i, token := range tokens {
+		if err := token.Verify(); err != nil {
+			return fmt.Errorf("token %d: %w", token.Serial, err)
+		}
+		if i == 0 {
+			continue
+		}
+		follows, err := token.Follows(tokens[i-1])
+		if err != nil {
+			return err
+		}
+		if !follows {
+			return fmt.Errorf("%w: at serial %d", ErrBrokenChain, token.Serial)
+		}
+	}
+	return nil
+}
+
+// signedData is the byte string covered by the token signature
+func (t *Token) signedData() ([]byte, error) {
+	unsigned := *t
+	unsigned.Signature = ""
+	data, err := canon.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode timestamp token: %w", err)
+	}
+	return append([]byte(tokenDomain), data...), nil
+}
+
+// imprintAlgorithm returns the hash algorithm of a multibase multihash
+func imprintAlgorithm(imprint string) (*crypto.HashAlgorithm, error) {
+	mh, err := crypto.DecodeMultibase(imprint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImprint, err)
+	}
+	algorithm, _, err := crypto.DecodeMultihash(mh)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImprint, err)
+	}
+	return algorithm, nil
+}
+
+// MemoryStorage keeps timestamp tokens in memory
+type MemoryStorage struct {
+	mu     sync.Mutex
+	tokens []*Token
+}
+
+// NewMemoryStorage creates an empty in-memory token storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// LastToken returns the latest stored token, or nil
+func (m *MemoryStorage) LastToken(ctx context.Context) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.tokens) == 0 {
+		return nil, nil
+	}
+	return m.tokens[len(m.tokens)-1], nil
+}
+
+// AppendToken stores the token following the latest one
+func (m *MemoryStorage) AppendToken(ctx context.Context, token *Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if token.Serial != uint64(len(m.tokens))+1 {
+		return fmt.Errorf("%w: serial %d taken", ErrBrokenChain, token.Serial)
+	}
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+// Tokens returns the stored tokens with serials from to to, inclusive
+func (m *MemoryStorage) Tokens(ctx context.Context, from, to uint64) ([]*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if from == 0 {
+		from = 1
+	}
+	if to > uint64(len(m.tokens)) {
+		to = uint64(len(m.tokens))
+	}
+	if from > to {
+		return []*Token{}, nil
+	}
+	return append([]*Token(nil), m.tokens[from-1:to]...), nil
+}
@@ -0,0 +1,68 @@
+package timestamp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"axia/internal/crypto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenChain(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	storage := NewMemoryStorage()
+	authority, err := NewAuthority(context.Background(), storage, key, logger)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		data := []byte(fmt.Sprintf("claim %d", i))
+		imprint, err := Imprint(data)
+		assert.NoError(t, err)
+
+		token, err := authority.Stamp(context.Background(), imprint)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i+1), token.Serial)
+		assert.NoError(t, token.Verify())
+
+		covers, err := token.Covers(data)
+		assert.NoError(t, err)
+		assert.True(t, covers)
+	}
+
+	_, err = authority.Stamp(context.Background(), "not an imprint")
+	assert.ErrorIs(t, err, ErrInvalidImprint)
+
+	tokens, err := authority.Tokens(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 3)
+	assert.NoError(t, VerifyChain(tokens))
+
+	// A reopened authority continues the chain
+	authority, err = NewAuthority(context.Background(), storage, key, logger)
+	assert.NoError(t, err)
+	imprint, err := Imprint([]byte("claim 3"))
+	assert.NoError(t, err)
+	token, err := authority.Stamp(context.Background(), imprint)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyChain(append(tokens, token)))
+
+	// Backdating a token breaks its signature, re-signing it breaks the chain
+	backdated := *tokens[1]
+	backdated.Time = backdated.Time.Add(-time.Hour)
+	assert.ErrorIs(t, backdated.Verify(), ErrInvalidToken)
+
+	data, err := backdated.signedData()
+	assert.NoError(t, err)
+	signature, err := key.Sign(data)
+	assert.NoError(t, err)
+	backdated.Signature = crypto.EncodeMultibase(signature)
+	assert.ErrorIs(t, VerifyChain([]*Token{tokens[0], &backdated, tokens[2]}), ErrBrokenChain)
+}
@@ -0,0 +1,273 @@
+package translog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"axia/internal/axiom"
+	"axia/internal/canon"
+	"axia/internal/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrLeafNotFound = errors.New("leaf not found in log")
+	ErrNoSigner     = errors.New("log has no signing key")
+)
+
+// Storage persists log entries. Append must be idempotent: appending a leaf
+// hash that is already stored returns its existing index.
+type Storage interface {
+	Append(ctx context.Context, leafHash, data []byte) (uint64, error)
+	LeafHashes(ctx context.Context) ([][]byte, error)
+}
+
+// Log is an append-only Merkle tree of accepted claims in the style of
+// Certificate Transparency (RFC 6962)
+type Log struct {
+	mu      sync.RWMutex
+	storage Storage
+	signer  crypto.Signer
+	leaves  [][]byte
+	index   map[string]uint64
+	logger  *logrus.Logger
+}
+
+// SignedTreeHead commits to the log's contents at a given size
+type SignedTreeHead struct {
+	TreeSize  uint64 `json:"tree_size"`
+	Timestamp int64  `json:"timestamp"`
+	RootHash  []byte `json:"root_hash"`
+	PublicKey string `json:"public_key"`
+	Signature []byte `json:"signature"`
+}
+
+// Open loads the log held by storage. The signer, typically the node key,
+// signs tree heads and may be nil for a read-only log.
+func Open(ctx context.Context, storage Storage, signer crypto.Signer, logger *logrus.Logger) (*Log, error) {
+	l := &Log{
+		storage: storage,
+		signer:  signer,
+		logger:  logger,
+	}
+	if err := l.reload(ctx); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) reload(ctx context.Context) error {
+	leaves, err := l.storage.LeafHashes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load log entries: %w", err)
+	}
+
+	l.leaves = leaves
+	l.index = make(map[string]uint64, len(leaves))
+	for i, leaf := range leaves {
+		l.index[string(leaf)] = uint64(i)
+	}
+	return nil
+}
+
+// ClaimLeaf returns the log entry for a claim: the canonical JSON of the
+// claim as its issuer signed it. Co-signatures, disclosures and timestamp
+// tokens are added after issuance and left out, so a claim has a single
+// entry however many of them it arrives with.
+func ClaimLeaf(claim *axiom.Claim) ([]byte, error) {
+	return canon.Marshal(axiom.UnsignedCopy(claim))
+}
+
+// AppendClaim adds a claim to the log and returns its leaf index
+func (l *Log) AppendClaim(ctx context.Context, claim *axiom.Claim) (uint64, error) {
+	data, err := ClaimLeaf(claim)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode claim for log: %w", err)
+	}
+	return l.Append(ctx, data)
+}
+
+// ClaimIndex returns the leaf index of a logged claim
+func (l *Log) ClaimIndex(claim *axiom.Claim) (uint64, error) {
+	data, err := ClaimLeaf(claim)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode claim for log: %w", err)
+	}
+	return l.LeafIndex(LeafHash(data))
+}
+
+// AppendRevocation adds a claim revocation to the log and returns its leaf
+// index
+func (l *Log) AppendRevocation(ctx context.Context, revocation *axiom.Revocation) (uint64, error) {
+	data, err := canon.Marshal(revocation)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode revocation for log: %w", err)
+	}
+	return l.Append(ctx, data)
+}
+
+// Append adds an entry to the log and returns its leaf index. Appending an
+// entry that is already logged returns the existing index.
+func (l *Log) Append(ctx context.Context, data []byte) (uint64, error) {
+	leafHash := LeafHash(data)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if index, ok := l.index[string(leafHash)]; ok {
+		return index, nil
+	}
+
+	index, err := l.storage.Append(ctx, leafHash, data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to append log entry: %w", err)
+	}
+
+	if index == uint64(len(l.leaves)) {
+		l.leaves = append(l.leaves, leafHash)
+		l.index[string(leafHash)] = index
+	} else if err := l.reload(ctx); err != nil {
+		// Another writer appended to the same storage
+		return 0, err
+	}
+
+	l.logger.WithFields(logrus.Fields{
+		"index":     index,
+		"leaf_hash": hex.EncodeToString(leafHash),
+	}).Debug("Appended entry to transparency log")
+	return index, nil
+}
+
+// Size returns the number of entries in the log
+func (l *Log) Size() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return uint64(len(l.leaves))
+}
+
+// LeafIndex returns the index of the entry with the given leaf hash
+func (l *Log) LeafIndex(leafHash []byte) (uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	index, ok := l.index[string(leafHash)]
+	if !ok {
+		return 0, ErrLeafNotFound
+	}
+	return index, nil
+}
+
+// RootHash returns the root of the tree made of the first size entries
+func (l *Log) RootHash(size uint64) ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if size > uint64(len(l.leaves)) {
+		return nil, ErrInvalidRange
+	}
+	return rootHash(l.leaves[:size]), nil
+}
+
+// InclusionProof returns the audit path of the entry at index in the tree
+// made of the first size entries
+func (l *Log) InclusionProof(index, size uint64) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if index >= size || size > uint64(len(l.leaves)) {
+		return nil, ErrInvalidRange
+	}
+	return inclusionPath(index, l.leaves[:size]), nil
+}
+
+// ConsistencyProof proves that the tree of size second extends the tree
+// of size first
+func (l *Log) ConsistencyProof(first, second uint64) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if first > second || second > uint64(len(l.leaves)) {
+		return nil, ErrInvalidRange
+	}
+	if first == 0 || first == second {
+		return [][]byte{}, nil
+	}
+	return consistencyPath(first, l.leaves[:second], true), nil
+}
+
+// SignedHead signs the log's current tree head
+func (l *Log) SignedHead() (*SignedTreeHead, error) {
+	if l.signer == nil {
+		return nil, ErrNoSigner
+	}
+
+	l.mu.RLock()
+	sth := &SignedTreeHead{
+		TreeSize:  uint64(len(l.leaves)),
+		Timestamp: time.Now().UnixMilli(),
+		RootHash:  rootHash(l.leaves),
+		PublicKey: hex.EncodeToString(l.signer.PublicKey()),
+	}
+	l.mu.RUnlock()
+
+	signature, err := l.signer.Sign(sth.signedData())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tree head: %w", err)
+	}
+	sth.Signature = signature
+	return sth, nil
+}
+
+// Verify checks the tree head's signature against the given log key
+func (sth *SignedTreeHead) Verify(publicKey ed25519.PublicKey) error {
+	if hex.EncodeToString(publicKey) != sth.PublicKey {
+		return fmt.Errorf("tree head is signed by another key: %s", sth.PublicKey)
+	}
+	return crypto.Verify(publicKey, sth.signedData(), sth.Signature)
+}
+
+// signedData is the byte string covered by the tree head signature
+func (sth *SignedTreeHead) signedData() []byte {
+	data := []byte("axia-sth-v1")
+	data = binary.BigEndian.AppendUint64(data, sth.TreeSize)
+	data = binary.BigEndian.AppendUint64(data, uint64(sth.Timestamp))
+	return append(data, sth.RootHash...)
+}
+
+// MemoryStorage keeps log entries in memory
+type MemoryStorage struct {
+	mu     sync.Mutex
+	leaves [][]byte
+}
+
+// NewMemoryStorage creates an empty in-memory log storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// Append stores a leaf hash unless it is already present
+func (m *MemoryStorage) Append(ctx context.Context, leafHash, data []byte) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, leaf := range m.leaves {
+		if string(leaf) == string(leafHash) {
+			return uint64(i), nil
+		}
+	}
+	m.leaves = append(m.leaves, leafHash)
+	return uint64(len(m.leaves) - 1), nil
+}
+
+// LeafHashes returns the stored leaf hashes in order
+func (m *MemoryStorage) LeafHashes(ctx context.Context) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]byte(nil), m.leaves...), nil
+}
@@ -0,0 +1,140 @@
+package translog
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"testing"
+
+	"axia/internal/axiom"
+	"axia/internal/crypto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLog(t *testing.T, entries int) (*Log, *crypto.KeyPair) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	l, err := Open(context.Background(), NewMemoryStorage(), key, logger)
+	assert.NoError(t, err)
+
+	for i := 0; i < entries; i++ {
+		index, err := l.Append(context.Background(), []byte(fmt.Sprintf("entry %d", i)))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i), index)
+	}
+	return l, key
+}
+
+func TestEmptyRoot(t *testing.T) {
+	l, _ := newTestLog(t, 0)
+	root, err := l.RootHash(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(root))
+}
+
+func TestAppendIsIdempotent(t *testing.T) {
+	l, _ := newTestLog(t, 3)
+
+	index, err := l.Append(context.Background(), []byte("entry 1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), index)
+	assert.Equal(t, uint64(3), l.Size())
+}
+
+func TestClaimHasOneEntry(t *testing.T) {
+	l, _ := newTestLog(t, 0)
+
+	manager := axiom.NewManager(l.logger)
+	for _, agent := range []string{"alice", "bob"} {
+		key, err := crypto.GenerateKeyPair()
+		assert.NoError(t, err)
+		manager.RegisterSigner(agent, key)
+	}
+	claim, err := manager.CreateClaim("alice", "carol", "Carol is reliable", 0.9, nil)
+	assert.NoError(t, err)
+
+	index, err := l.AppendClaim(context.Background(), claim)
+	assert.NoError(t, err)
+
+	// Co-signatures added later leave the entry unchanged
+	assert.NoError(t, manager.CoSign(claim, "bob"))
+	cosigned, err := l.AppendClaim(context.Background(), claim)
+	assert.NoError(t, err)
+	assert.Equal(t, index, cosigned)
+	assert.Equal(t, uint64(1), l.Size())
+}
+
+func TestInclusionProofs(t *testing.T) {
+	l, _ := newTestLog(t, 13)
+
+	for size := uint64(1); size <= l.Size(); size++ {
+		root, err := l.RootHash(size)
+		assert.NoError(t, err)
+
+		for index := uint64(0); index < size; index++ {
+			proof, err := l.InclusionProof(index, size)
+			assert.NoError(t, err)
+
+			leaf := LeafHash([]byte(fmt.Sprintf("entry %d", index)))
+			assert.NoError(t, VerifyInclusion(leaf, index, size, proof, root), "index %d size %d", index, size)
+
+			other := LeafHash([]byte("forged"))
+			assert.Error(t, VerifyInclusion(other, index, size, proof, root))
+		}
+	}
+}
+
+func TestConsistencyProofs(t *testing.T) {
+	l, _ := newTestLog(t, 13)
+
+	for second := uint64(1); second <= l.Size(); second++ {
+		root2, err := l.RootHash(second)
+		assert.NoError(t, err)
+
+		for first := uint64(1); first <= second; first++ {
+			root1, err := l.RootHash(first)
+			assert.NoError(t, err)
+
+			proof, err := l.ConsistencyProof(first, second)
+			assert.NoError(t, err)
+			assert.NoError(t, VerifyConsistency(first, second, root1, root2, proof), "%d -> %d", first, second)
+
+			if first < second {
+				forged := LeafHash([]byte("forged"))
+				assert.Error(t, VerifyConsistency(first, second, forged, root2, proof))
+			}
+		}
+	}
+}
+
+func TestSignedHead(t *testing.T) {
+	l, key := newTestLog(t, 5)
+
+	sth, err := l.SignedHead()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), sth.TreeSize)
+	assert.NoError(t, sth.Verify(key.PublicKey()))
+
+	sth.TreeSize = 4
+	assert.Error(t, sth.Verify(key.PublicKey()))
+}
+
+func TestRFC6962Root(t *testing.T) {
+	// Test vector from the Certificate Transparency reference implementation
+	inputs := []string{"", "00", "10", "2021", "3031", "40414243",
+		"5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+
+	leaves := make([][]byte, len(inputs))
+	for i, input := range inputs {
+		data, err := hex.DecodeString(input)
+		assert.NoError(t, err)
+		leaves[i] = LeafHash(data)
+	}
+	assert.Equal(t, "5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328", hex.EncodeToString(rootHash(leaves)))
+}
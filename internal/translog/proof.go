@@ -0,0 +1,89 @@
+package translog
+
+// InclusionProof shows that an entry is part of the tree with a given root
+type InclusionProof struct {
+	LeafIndex uint64   `json:"leaf_index"`
+	TreeSize  uint64   `json:"tree_size"`
+	LeafHash  []byte   `json:"leaf_hash"`
+	RootHash  []byte   `json:"root_hash"`
+	AuditPath [][]byte `json:"audit_path"`
+}
+
+// ConsistencyProof shows that the second tree is an append-only extension
+// of the first
+type ConsistencyProof struct {
+	FirstSize  uint64   `json:"first_size"`
+	SecondSize uint64   `json:"second_size"`
+	FirstRoot  []byte   `json:"first_root"`
+	SecondRoot []byte   `json:"second_root"`
+	Path       [][]byte `json:"path"`
+}
+
+// ProveInclusion builds the inclusion proof of the entry with leafHash in
+// the tree of the given size; a zero size means the current tree
+func (l *Log) ProveInclusion(leafHash []byte, size uint64) (*InclusionProof, error) {
+	if size == 0 {
+		size = l.Size()
+	}
+
+	index, err := l.LeafIndex(leafHash)
+	if err != nil {
+		return nil, err
+	}
+	path, err := l.InclusionProof(index, size)
+	if err != nil {
+		return nil, err
+	}
+	root, err := l.RootHash(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InclusionProof{
+		LeafIndex: index,
+		TreeSize:  size,
+		LeafHash:  leafHash,
+		RootHash:  root,
+		AuditPath: path,
+	}, nil
+}
+
+// ProveConsistency builds the consistency proof between two tree sizes; a
+// zero second size means the current tree
+func (l *Log) ProveConsistency(first, second uint64) (*ConsistencyProof, error) {
+	if second == 0 {
+		second = l.Size()
+	}
+
+	path, err := l.ConsistencyProof(first, second)
+	if err != nil {
+		return nil, err
+	}
+	firstRoot, err := l.RootHash(first)
+	if err != nil {
+		return nil, err
+	}
+	secondRoot, err := l.RootHash(second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsistencyProof{
+		FirstSize:  first,
+		SecondSize: second,
+		FirstRoot:  firstRoot,
+		SecondRoot: secondRoot,
+		Path:       path,
+	}, nil
+}
+
+// Verify checks the proof against its own root hash; callers must compare
+// that root with a signed tree head they trust
+func (p *InclusionProof) Verify() error {
+	return VerifyInclusion(p.LeafHash, p.LeafIndex, p.TreeSize, p.AuditPath, p.RootHash)
+}
+
+// Verify checks the proof against its own root hashes
+func (p *ConsistencyProof) Verify() error {
+	return VerifyConsistency(p.FirstSize, p.SecondSize, p.FirstRoot, p.SecondRoot, p.Path)
+}
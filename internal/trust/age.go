@@ -0,0 +1,136 @@
+package trust
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"axia/internal/axiom"
+	"axia/internal/graph"
+)
+
+var ErrInvalidHalfLife = errors.New("invalid half-life")
+
+// HalfLives configures how fast claims lose weight with age. A claim
+// keeps half its weight after each half-life since it was issued. A zero
+// half-life keeps claims at full weight.
+type HalfLives struct {
+	// Default applies to claims without a tag in Tags
+	Default time.Duration
+	// Tags holds the half-lives of claims by tag. A claim with several
+	// configured tags ages by the longest of their half-lives.
+	Tags map[string]time.Duration
+}
+
+// ParseHalfLives parses a default half-life and a comma-separated list of
+// tag=half-life pairs, such as "security=730d,market=7d". Half-lives are
+// Go durations or a number of days ending in d. Empty specs configure no
+// aging.
+func ParseHalfLives(defaultSpec, tagSpec string) (HalfLives, error) {
+	halfLives := HalfLives{Tags: make(map[string]time.Duration)}
+
+	var err error
+	if halfLives.Default, err = parseHalfLife(defaultSpec); err != nil {
+		return HalfLives{}, err
+	}
+	for _, pair := range strings.Split(tagSpec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		tag, spec, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(tag) == "" {
+			return HalfLives{}, fmt.Errorf("%w: %q is not tag=half-life", ErrInvalidHalfLife, pair)
+		}
+		if halfLives.Tags[strings.TrimSpace(tag)], err = parseHalfLife(spec); err != nil {
+			return HalfLives{}, err
+		}
+	}
+	return halfLives, nil
+}
+
+func parseHalfLife(spec string) (time.Duration, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return 0, nil
+	}
+
+	var halfLife time.Duration
+	if strings.HasSuffix(spec, "d") {
+		value, err := strconv.ParseFloat(strings.TrimSuffix(spec, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidHalfLife, spec)
+		}
+		halfLife = time.Duration(value * float64(24*time.Hour))
+	} else {
+		value, err := time.ParseDuration(spec)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidHalfLife, spec)
+		}
+		halfLife = value
+	}
+	if halfLife < 0 {
+		return 0, fmt.Errorf("%w: %q is negative", ErrInvalidHalfLife, spec)
+	}
+	return halfLife, nil
+}
+
+// HalfLife returns the half-life of the claim by the tags it shows
+func (h HalfLives) HalfLife(claim *axiom.Claim) time.Duration {
+	halfLife, tagged := h.Default, false
+	for _, tag := range claim.ClaimBody.Tags {
+		value, ok := h.Tags[tag]
+		if !ok {
+			continue
+		}
+		// Zero, no aging at all, is the longest half-life
+		if !tagged || value == 0 || (halfLife != 0 && value > halfLife) {
+			halfLife = value
+		}
+		tagged = true
+	}
+	return halfLife
+}
+
+// issuedAt returns when the claim was issued at the latest: its own date, or
+// the time of its timestamp token if that is earlier. Issuers choose their
+// claims' dates, an authority the token's.
+func issuedAt(claim *axiom.Claim) time.Time {
+	issued := claim.Issued
+	if token := claim.Proof.Timestamp; token != nil && token.Time.Before(issued) {
+		issued = token.Time
+	}
+	return issued
+}
+
+// Freshness returns the share of its weight the claim keeps at time now,
+// 0.5^(age / half-life). Claims dated further than axiom.MaxClockSkew in
+// the future are misdated and keep no weight.
+func (h HalfLives) Freshness(claim *axiom.Claim, now time.Time) float64 {
+	age := now.Sub(issuedAt(claim))
+	if age < -axiom.MaxClockSkew {
+		return 0
+	}
+
+	halfLife := h.HalfLife(claim)
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// freshness returns the share of its weight the claim keeps now
+func (n *Network) freshness(claim *axiom.Claim) float64 {
+	return n.halfLives.Freshness(claim, n.now())
+}
+
+// edgeWeight returns the weight of a trust edge at the age of its claim
+func (n *Network) edgeWeight(edge *graph.Edge) float64 {
+	claim, ok := edge.Data.(*axiom.Claim)
+	if !ok {
+		return edge.Weight
+	}
+	return edge.Weight * n.freshness(claim)
+}
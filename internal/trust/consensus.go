@@ -0,0 +1,176 @@
+package trust
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"axia/internal/axiom"
+	"axia/internal/subjective"
+)
+
+// ClusterGap is the smallest difference in confidence that separates two
+// clusters of issuers
+const ClusterGap = 0.2
+
+var ErrNoConsensusTopic = errors.New("consensus needs a subject or an axiom")
+
+// ConsensusReport summarizes how far the issuers reached by a query agree
+// on a subject or axiom. Every issuer counts once, with its latest claim,
+// weighed by the observer's trust in it and the claim's freshness.
+type ConsensusReport struct {
+	Subject string `json:"subject,omitempty"`
+	Axiom   string `json:"axiom,omitempty"`
+	// Issuers is the number of independent issuers weighed
+	Issuers int `json:"issuers"`
+	// Mean and Variance are the trust-weighted mean and variance of the
+	// issuers' confidence
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	// Clusters groups issuers of similar confidence, heaviest first
+	Clusters []*Cluster `json:"clusters"`
+	// Score is 1 for many issuers in full agreement and falls towards 0
+	// as they disagree or become few
+	Score float64 `json:"score"`
+	// Opinion fuses the observer's opinions through every issuer with
+	// the query's fusion operator
+	Opinion subjective.Opinion `json:"opinion"`
+}
+
+// Cluster is a group of issuers whose confidence lies within ClusterGap of
+// its neighbours
+type Cluster struct {
+	// Confidence is the trust-weighted mean confidence of the cluster
+	Confidence float64 `json:"confidence"`
+	// Weight is the cluster's share of the total trust
+	Weight  float64  `json:"weight"`
+	Issuers []string `json:"issuers"`
+}
+
+// opinion is an issuer's latest weighed claim
+type opinion struct {
+	issuer     string
+	claim      *axiom.Claim
+	confidence float64
+	trust      float64
+	opinion    subjective.Opinion
+}
+
+// Consensus reports the agreement among the claims a query finds on
+// opts.Subject or opts.Axiom
+func (n *Network) Consensus(opts QueryOptions) (*ConsensusReport, error) {
+	if opts.Subject == "" && opts.Axiom == "" {
+		return nil, ErrNoConsensusTopic
+	}
+	fusion := opts.Fusion
+	if fusion == "" {
+		fusion = subjective.FusionCumulative
+	}
+	if _, err := subjective.Fuse(fusion); err != nil {
+		return nil, err
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	results, err := n.query(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Issuers are independent voices; re-issuing or co-signing does not
+	// add weight, so each counts once with its latest claim, weighed by
+	// trust and the claim's freshness
+	latest := make(map[string]*opinion)
+	for _, result := range results {
+		claim := result.Claim
+		if result.Trust <= 0 || !axiom.Reveals(claim, axiom.DisclosableConfidence) {
+			continue
+		}
+		previous, ok := latest[claim.Issuer]
+		if ok && !laterClaim(claim, previous.claim) {
+			continue
+		}
+		latest[claim.Issuer] = &opinion{
+			issuer:     claim.Issuer,
+			claim:      claim,
+			confidence: claim.ClaimBody.Rating.ConfidenceValue,
+			trust:      result.Trust * result.Freshness,
+			opinion:    result.Opinion,
+		}
+	}
+
+	report := &ConsensusReport{
+		Subject:  opts.Subject,
+		Axiom:    opts.Axiom,
+		Issuers:  len(latest),
+		Clusters: make([]*Cluster, 0),
+		Opinion:  subjective.Vacuous(subjective.DefaultBaseRate),
+	}
+	if len(latest) == 0 {
+		return report, nil
+	}
+
+	opinions := make([]*opinion, 0, len(latest))
+	var total float64
+	for _, o := range latest {
+		opinions = append(opinions, o)
+		total += o.trust
+	}
+	sort.Slice(opinions, func(i, j int) bool {
+		if opinions[i].confidence != opinions[j].confidence {
+			return opinions[i].confidence < opinions[j].confidence
+		}
+		return opinions[i].issuer < opinions[j].issuer
+	})
+
+	// Fused in a fixed order, since rounding depends on it
+	subjectives := make([]subjective.Opinion, 0, len(opinions))
+	for _, o := range opinions {
+		subjectives = append(subjectives, o.opinion)
+	}
+	if report.Opinion, err = subjective.Fuse(fusion, subjectives...); err != nil {
+		return nil, err
+	}
+
+	for _, o := range opinions {
+		report.Mean += o.trust * o.confidence / total
+	}
+	for _, o := range opinions {
+		report.Variance += o.trust * (o.confidence - report.Mean) * (o.confidence - report.Mean) / total
+	}
+
+	// Sorted confidences split into clusters wherever neighbours are more
+	// than ClusterGap apart
+	var cluster *Cluster
+	for i, o := range opinions {
+		if i == 0 || o.confidence-opinions[i-1].confidence > ClusterGap {
+			cluster = &Cluster{}
+			report.Clusters = append(report.Clusters, cluster)
+		}
+		cluster.Confidence += o.trust * o.confidence
+		cluster.Weight += o.trust
+		cluster.Issuers = append(cluster.Issuers, o.issuer)
+	}
+	for _, cluster := range report.Clusters {
+		cluster.Confidence /= cluster.Weight
+		cluster.Weight /= total
+	}
+	sort.SliceStable(report.Clusters, func(i, j int) bool {
+		return report.Clusters[i].Weight > report.Clusters[j].Weight
+	})
+
+	// Confidences lie in 0..1, so the variance is at most 0.25
+	agreement := math.Max(0, 1-4*report.Variance)
+	support := float64(report.Issuers) / float64(report.Issuers+1)
+	report.Score = agreement * support
+	return report, nil
+}
+
+// laterClaim reports whether claim supersedes previous in its signer's
+// history. Sequences only order the claims of one issuer.
+func laterClaim(claim, previous *axiom.Claim) bool {
+	if claim.Issuer == previous.Issuer && claim.Sequence != 0 && previous.Sequence != 0 {
+		return claim.Sequence > previous.Sequence
+	}
+	return claim.Issued.After(previous.Issued)
+}
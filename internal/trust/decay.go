@@ -0,0 +1,179 @@
+package trust
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"axia/internal/axiom"
+	"axia/internal/graph"
+)
+
+// Decay models
+const (
+	DecayExponential = "exponential"
+	DecayLinear      = "linear"
+	DecayCutoff      = "cutoff"
+)
+
+// T-norms combining trust along a path
+const (
+	TNormProduct     = "product"
+	TNormMinimum     = "minimum"
+	TNormLukasiewicz = "lukasiewicz"
+)
+
+// DefaultDecayRate is the share of trust kept per hop by exponential decay
+const DefaultDecayRate = 0.5
+
+var (
+	ErrUnknownDecayModel = errors.New("unknown decay model")
+	ErrUnknownTNorm      = errors.New("unknown t-norm")
+)
+
+// DecayModel discounts trust with the number of hops it travelled
+type DecayModel interface {
+	// Factor returns the share of trust kept after hops hops
+	Factor(hops int) float64
+}
+
+// ExponentialDecay keeps Rate of the trust at every hop
+type ExponentialDecay struct {
+	Rate float64
+}
+
+// Factor returns Rate^hops
+func (d ExponentialDecay) Factor(hops int) float64 {
+	return math.Pow(d.Rate, float64(hops))
+}
+
+// LinearDecay loses an equal share of trust at every hop, keeping a
+// little at MaxHops and none beyond
+type LinearDecay struct {
+	MaxHops int
+}
+
+// Factor returns 1 - hops / (MaxHops + 1), at least 0
+func (d LinearDecay) Factor(hops int) float64 {
+	return math.Max(0, 1-float64(hops)/float64(d.MaxHops+1))
+}
+
+// CutoffDecay keeps all trust up to MaxHops and none beyond
+type CutoffDecay struct {
+	MaxHops int
+}
+
+// Factor returns 1 up to MaxHops hops and 0 beyond
+func (d CutoffDecay) Factor(hops int) float64 {
+	if hops > d.MaxHops {
+		return 0
+	}
+	return 1
+}
+
+// NewDecayModel returns the named decay model. rate applies to exponential
+// decay, maxHops to linear decay and cutoffs.
+func NewDecayModel(name string, rate float64, maxHops int) (DecayModel, error) {
+	switch name {
+	case DecayExponential:
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("decay rate %v is outside 0..1", rate)
+		}
+		return ExponentialDecay{Rate: rate}, nil
+	case DecayLinear:
+		return LinearDecay{MaxHops: maxHops}, nil
+	case DecayCutoff:
+		return CutoffDecay{MaxHops: maxHops}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDecayModel, name)
+	}
+}
+
+// TNorm combines the trust accumulated along a path with the weight of its
+// next edge
+type TNorm func(a, b float64) float64
+
+// Product multiplies trust along the path
+func Product(a, b float64) float64 {
+	return a * b
+}
+
+// Minimum keeps the weakest link of the path
+func Minimum(a, b float64) float64 {
+	return math.Min(a, b)
+}
+
+// Lukasiewicz loses 1 - b of the trust at each edge
+func Lukasiewicz(a, b float64) float64 {
+	return math.Max(0, a+b-1)
+}
+
+// LookupTNorm returns the named t-norm
+func LookupTNorm(name string) (TNorm, error) {
+	switch name {
+	case TNormProduct:
+		return Product, nil
+	case TNormMinimum:
+		return Minimum, nil
+	case TNormLukasiewicz:
+		return Lukasiewicz, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTNorm, name)
+	}
+}
+
+// pathTrust returns the observer's trust in every agent within opts.Depth
+// hops: the t-norm of the edge weights along the best path, discounted by
+// the decay model for the path's length when opts.UseTrustDecay is set
+func (n *Network) pathTrust(opts QueryOptions) map[string]float64 {
+	trust := map[string]float64{opts.Observer: 1}
+	observer, ok := n.graph.Lookup(opts.Observer)
+	if !ok {
+		return trust
+	}
+
+	tnorm := opts.TNorm
+	if tnorm == nil {
+		tnorm = Product
+	}
+	decay := opts.Decay
+	if decay == nil {
+		decay = ExponentialDecay{Rate: DefaultDecayRate}
+	}
+
+	// best holds the highest undecayed trust in a node over paths of any
+	// length so far. All decay models discount longer paths at least as
+	// much, so a longer path only counts if it carries more trust.
+	best := map[*graph.Node]float64{observer: 1}
+	layer := map[*graph.Node]float64{observer: 1}
+	for hop := 1; hop <= opts.Depth && len(layer) > 0; hop++ {
+		next := make(map[*graph.Node]float64)
+		for node, value := range layer {
+			for _, edge := range n.graph.Outgoing(node) {
+				claim, ok := edge.Data.(*axiom.Claim)
+				if !ok || edge.Weight <= 0 || (!opts.IncludeRevoked && n.isRevoked(claim)) {
+					continue
+				}
+				combined := tnorm(value, n.edgeWeight(edge))
+				if combined <= best[edge.To] || combined <= next[edge.To] {
+					continue
+				}
+				next[edge.To] = combined
+			}
+		}
+
+		for node, value := range next {
+			best[node] = value
+			agent, ok := node.Data.(string)
+			if !ok {
+				continue
+			}
+			if opts.UseTrustDecay {
+				value *= decay.Factor(hop)
+			}
+			trust[agent] = math.Max(trust[agent], value)
+		}
+		layer = next
+	}
+	return trust
+}
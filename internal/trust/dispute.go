@@ -0,0 +1,337 @@
+package trust
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"axia/internal/axiom"
+	"axia/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+// DisputeThreshold is the difference in confidence past which two claims
+// of the same axiom about the same subject contradict each other
+const DisputeThreshold = 0.5
+
+// Kinds of dispute
+const (
+	// DisputeDivergent claims rate the same axiom far apart
+	DisputeDivergent = "divergent"
+	// DisputeNegated claims assert an axiom and its negation
+	DisputeNegated = "negated"
+)
+
+// Dispute is a pair of contradicting claims by different signers about
+// the same subject. It is withdrawn when either claim is revoked or
+// superseded by a later claim of its signers.
+type Dispute struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	// Axiom is the normalized statement both claims rate, without
+	// negation
+	Axiom string `json:"axiom"`
+	// Divergence is the difference in confidence in Axiom, a negated
+	// claim of confidence c counting as 1 - c
+	Divergence float64        `json:"divergence"`
+	Claims     []*axiom.Claim `json:"claims"`
+}
+
+// negations invert the statement they appear in
+var negations = map[string]bool{"not": true, "no": true, "never": true}
+
+// contractions are negations that do not end in n't, with their base word
+var contractions = map[string]string{"cannot": "can", "can't": "can", "won't": "will"}
+
+// normalizeAxiom reduces a statement to lowercase words without
+// punctuation or negations, and reports whether it was negated. "The
+// contract is NOT safe." and "¬ the contract is safe" both normalize to
+// "the contract is safe", negated.
+func normalizeAxiom(statement string) (string, bool) {
+	statement = strings.ToLower(strings.TrimSpace(statement))
+	statement = strings.ReplaceAll(statement, "’", "'")
+
+	negated := false
+	for strings.HasPrefix(statement, "¬") || strings.HasPrefix(statement, "!") {
+		negated = !negated
+		statement = strings.TrimLeft(strings.TrimPrefix(strings.TrimPrefix(statement, "¬"), "!"), " ")
+	}
+
+	words := make([]string, 0)
+	for _, word := range strings.Fields(statement) {
+		word = strings.Trim(word, ".,;:!?\"'()")
+		if base, ok := contractions[word]; ok {
+			negated = !negated
+			word = base
+		} else if strings.HasSuffix(word, "n't") {
+			negated = !negated
+			word = strings.TrimSuffix(word, "n't")
+		} else if negations[word] {
+			negated = !negated
+			continue
+		}
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " "), negated
+}
+
+// stance is an issuer's confidence in a normalized axiom
+type stance struct {
+	claim      *axiom.Claim
+	axiom      string
+	negated    bool
+	confidence float64
+}
+
+// stanceOf returns the stance a public claim, or a Disclose view of it,
+// takes, or false if it conceals its axiom or confidence
+func stanceOf(claim *axiom.Claim) (*stance, bool) {
+	if !axiom.Reveals(claim, axiom.DisclosableAxiom) || !axiom.Reveals(claim, axiom.DisclosableConfidence) {
+		return nil, false
+	}
+	statement, negated := normalizeAxiom(claim.ClaimBody.Rating.Axiom)
+	if statement == "" {
+		return nil, false
+	}
+
+	confidence := claim.ClaimBody.Rating.ConfidenceValue
+	if negated {
+		confidence = 1 - confidence
+	}
+	return &stance{claim: claim, axiom: statement, negated: negated, confidence: confidence}, true
+}
+
+// latestStances returns the latest stance of every signer of a public,
+// unrevoked claim on subject about the normalized axiom. Co-signers take
+// the stance of the claims they co-sign.
+func (n *Network) latestStances(subject, statement string) map[string]*stance {
+	latest := make(map[string]*stance)
+	for _, other := range n.claims {
+		if other.IsPrivate() || n.isRevoked(other) || other.ClaimBody.Subject != subject {
+			continue
+		}
+		disclosed, err := axiom.Disclose(other)
+		if err != nil {
+			continue
+		}
+		s, ok := stanceOf(disclosed)
+		if !ok || s.axiom != statement {
+			continue
+		}
+		for _, signer := range other.Signers() {
+			if previous, ok := latest[signer]; !ok || laterClaim(other, previous.claim) {
+				latest[signer] = s
+			}
+		}
+	}
+	return latest
+}
+
+// standing reports whether claim is still the latest word of one of its
+// signers
+func standing(claim *axiom.Claim, latest map[string]*stance) bool {
+	for _, signer := range claim.Signers() {
+		if s, ok := latest[signer]; ok && s.claim.Proof.ProofValue == claim.Proof.ProofValue {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDisputes records the disputes between a new public claim and the
+// latest claims of other signers on the same subject and axiom
+func (n *Network) checkDisputes(claim *axiom.Claim) {
+	current, ok := stanceOf(claim)
+	if !ok {
+		return
+	}
+
+	// Only the signers' latest word counts
+	latest := n.latestStances(claim.ClaimBody.Subject, current.axiom)
+	if !standing(claim, latest) {
+		return
+	}
+
+	signedCurrent := make(map[string]bool)
+	for _, signer := range claim.Signers() {
+		signedCurrent[signer] = true
+	}
+	signers := make([]string, 0, len(latest))
+	for signer := range latest {
+		signers = append(signers, signer)
+	}
+	sort.Strings(signers)
+
+	compared := make(map[string]bool)
+	for _, signer := range signers {
+		other := latest[signer]
+		if signedCurrent[signer] || compared[other.claim.Proof.ProofValue] {
+			continue
+		}
+		compared[other.claim.Proof.ProofValue] = true
+
+		divergence := math.Abs(current.confidence - other.confidence)
+		if divergence <= DisputeThreshold || n.hasDispute(other.claim, claim) {
+			continue
+		}
+
+		dispute := &Dispute{
+			Kind:       DisputeDivergent,
+			Subject:    claim.ClaimBody.Subject,
+			Axiom:      current.axiom,
+			Divergence: divergence,
+			Claims:     []*axiom.Claim{other.claim, claim},
+		}
+		if current.negated != other.negated {
+			dispute.Kind = DisputeNegated
+		}
+		n.logger.WithFields(logrus.Fields{
+			"subject": dispute.Subject,
+			"axiom":   dispute.Axiom,
+			"kind":    dispute.Kind,
+		}).Warn("Claims disputed")
+		n.disputes = append(n.disputes, dispute)
+	}
+}
+
+// hasDispute reports whether a dispute between a and b is recorded
+func (n *Network) hasDispute(a, b *axiom.Claim) bool {
+	for _, dispute := range n.disputes {
+		first, second := dispute.Claims[0].Proof.ProofValue, dispute.Claims[1].Proof.ProofValue
+		if (first == a.Proof.ProofValue && second == b.Proof.ProofValue) ||
+			(first == b.Proof.ProofValue && second == a.Proof.ProofValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneDisputes drops the disputes on subject of which a claim has been
+// revoked or superseded by its signers
+func (n *Network) pruneDisputes(subject string) {
+	kept := make([]*Dispute, 0, len(n.disputes))
+	stances := make(map[string]map[string]*stance)
+	for _, dispute := range n.disputes {
+		if dispute.Subject != subject {
+			kept = append(kept, dispute)
+			continue
+		}
+		latest, ok := stances[dispute.Axiom]
+		if !ok {
+			latest = n.latestStances(subject, dispute.Axiom)
+			stances[dispute.Axiom] = latest
+		}
+		if standing(dispute.Claims[0], latest) && standing(dispute.Claims[1], latest) {
+			kept = append(kept, dispute)
+			continue
+		}
+		n.logger.WithFields(logrus.Fields{
+			"subject": dispute.Subject,
+			"axiom":   dispute.Axiom,
+		}).Info("Dispute withdrawn")
+	}
+	n.disputes = kept
+}
+
+// reviewDisputes re-evaluates the disputes on subject after claims about it
+// were revoked: disputes of revoked claims are dropped, and the claims they
+// superseded are checked again
+func (n *Network) reviewDisputes(subject string) {
+	n.pruneDisputes(subject)
+
+	claims := make([]*axiom.Claim, 0)
+	for _, claim := range n.claims {
+		if !claim.IsPrivate() && claim.ClaimBody.Subject == subject {
+			claims = append(claims, claim)
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Issued.Before(claims[j].Issued)
+	})
+	for _, claim := range claims {
+		if disclosed, err := axiom.Disclose(claim); err == nil {
+			n.checkDisputes(disclosed)
+		}
+	}
+	n.updateDisputed(subject)
+}
+
+// updateDisputed marks subject disputed while disputes about it remain, and
+// resolves it once they are all withdrawn
+func (n *Network) updateDisputed(subject string) {
+	node, ok := n.graph.Lookup(subject)
+	if !ok || node.State == nil {
+		return
+	}
+
+	disputed := false
+	for _, dispute := range n.disputes {
+		if dispute.Subject == subject {
+			disputed = true
+			break
+		}
+	}
+
+	var err error
+	if disputed {
+		err = node.State.Dispute(context.Background())
+	} else {
+		err = node.State.Resolve(context.Background())
+	}
+	if err != nil {
+		n.logger.WithError(err).Error("Failed to update dispute state of subject")
+	}
+}
+
+// IsDisputed reports whether contradicting claims were made about the
+// subject
+func (n *Network) IsDisputed(subject string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	node, ok := n.graph.Lookup(subject)
+	return ok && node.State != nil && node.State.FSM.Current() == state.StateDisputed
+}
+
+// Disputes returns the disputes recorded so far, in the order found
+func (n *Network) Disputes() []*Dispute {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return append([]*Dispute(nil), n.disputes...)
+}
+
+// Conflicts returns the recorded disputes between claims the query finds,
+// matching opts.Axiom by its normalized statement. Both sides of a dispute
+// are searched whatever their axiom or confidence.
+func (n *Network) Conflicts(opts QueryOptions) ([]*Dispute, error) {
+	statement, _ := normalizeAxiom(opts.Axiom)
+	opts.Axiom = ""
+	opts.MinConfidence = 0
+	opts.MaxConfidence = 1
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	results, err := n.query(opts)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(results))
+	for _, result := range results {
+		found[result.Claim.Proof.ProofValue] = true
+	}
+
+	conflicts := make([]*Dispute, 0)
+	for _, dispute := range n.disputes {
+		if statement != "" && dispute.Axiom != statement {
+			continue
+		}
+		if found[dispute.Claims[0].Proof.ProofValue] && found[dispute.Claims[1].Proof.ProofValue] {
+			conflicts = append(conflicts, dispute)
+		}
+	}
+	return conflicts, nil
+}
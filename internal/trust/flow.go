@@ -0,0 +1,39 @@
+package trust
+
+import "axia/internal/graph"
+
+// MinFlowConfidence is the least confidence of a claim that endorses its
+// subject in the flow metric
+const MinFlowConfidence = 0.5
+
+// flowTrust fully trusts the agents accepted by the Advogato flow metric
+// from opts.Seeds, or the observer, along each signer's latest endorsements
+func (n *Network) flowTrust(opts QueryOptions) map[string]float64 {
+	seeds := opts.Seeds
+	if len(seeds) == 0 {
+		seeds = []string{opts.Observer}
+	}
+	capacities := opts.Capacities
+	if len(capacities) == 0 {
+		capacities = graph.DefaultFlowCapacities
+	}
+
+	roots := make([]*graph.Node, 0, len(seeds))
+	for _, seed := range seeds {
+		if node, ok := n.graph.Lookup(seed); ok {
+			roots = append(roots, node)
+		}
+	}
+	latest := n.followLatest()
+	endorses := func(edge *graph.Edge) bool {
+		return n.edgeWeight(edge) >= MinFlowConfidence && latest(edge)
+	}
+
+	trust := make(map[string]float64)
+	for node := range n.graph.FlowTrust(roots, capacities, endorses) {
+		if agent, ok := node.Data.(string); ok {
+			trust[agent] = 1
+		}
+	}
+	return trust
+}
@@ -1,40 +1,177 @@
 package trust
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"axia/internal/axiom"
+	"axia/internal/crypto"
+	"axia/internal/did"
 	"axia/internal/graph"
+	"axia/internal/status"
+	"axia/internal/subjective"
+	"axia/internal/translog"
 )
 
-// Network represents a trust network of axiomatic claims
+// Network represents a trust network of axiomatic claims. It is safe for
+// concurrent use: claims and revocations are added under a write lock and
+// queries run under a read lock.
 type Network struct {
+	mu      sync.RWMutex
 	graph   *graph.Graph
 	claims  map[string]*axiom.Claim
+	revoked map[revocationKey]*revocationEntry
+	status  *status.List
+	// revocations checks revocations against the claims they withdraw
+	revocations RevocationVerifier
+	// equivocations holds the duplicate sequences and forks seen so far
+	equivocations []*axiom.Equivocation
+	// disputes holds the contradicting claims seen so far
+	disputes []*Dispute
+	log     *translog.Log
+	// resolver binds the issuers and co-signers of claims to their keys
+	resolver did.Resolver
+	// stamper timestamps accepted claims that have no timestamp from it or
+	// from one of the trusted authorities yet
+	stamper axiom.Stamper
+	trustedAuthorities []string
+	// halfLives ages claims, which are as old as now says
+	halfLives HalfLives
+	now       func() time.Time
 	logger  *logrus.Logger
 }
 
+// RevocationVerifier checks that a revocation is authorized by the issuer
+// of the claim it withdraws, such as axiom.Manager
+type RevocationVerifier interface {
+	VerifyRevocation(ctx context.Context, revocation *axiom.Revocation, claim *axiom.Claim) error
+}
+
+// revocationKey identifies the claim a revocation withdraws. Claim IDs are
+// chosen by issuers, so they are only unique per issuer.
+type revocationKey struct {
+	issuer  string
+	claimID string
+}
+
+// revocationEntry is a revocation with the status list index it sets once
+// verified against the claim it withdraws
+type revocationEntry struct {
+	revocation  *axiom.Revocation
+	statusIndex uint64
+	verified    bool
+}
+
 // QueryOptions represents filtering options for trust network queries
 type QueryOptions struct {
+	// Observer, when set, limits results to claims signed by the observer
+	// or by agents within Depth trust hops of it
 	Observer       string
 	Agent         string
 	Subject       string
+	// Axiom, when set, limits results to claims of this statement
+	Axiom         string
+	// Tags, when set, limits results to claims showing one of these tags
 	Tags          []string
 	Depth         int
 	MinConfidence float64
 	MaxConfidence float64
 	UseConsensus  bool
+	// UseTrustDecay discounts trust in distant agents with Decay, by
+	// default exponential decay at DefaultDecayRate
 	UseTrustDecay bool
+	Decay         DecayModel
+	// TNorm combines edge weights along trust paths, Product by default
+	TNorm         TNorm
+	// Rank selects how the observer's trust in agents is computed,
+	// RankPath by default
+	Rank          string
+	// Seeds are the roots of RankFlow, the observer by default, and
+	// Capacities their flow capacities by distance,
+	// graph.DefaultFlowCapacities by default
+	Seeds         []string
+	Capacities    []int
+	// Fusion combines the issuers' opinions in consensus reports,
+	// subjective.FusionCumulative by default
+	Fusion        string
+	// IncludeRevoked returns revoked claims too; they are excluded by default
+	IncludeRevoked bool
+	// Keys are the observer's keys. Private claims are decrypted with them
+	// and left out if none is a recipient.
+	Keys []*crypto.KeyPair
+}
+
+// Result is a claim found by a query, weighed from the observer's view
+type Result struct {
+	Claim *axiom.Claim `json:"claim"`
+	// Distance is the fewest trust hops from the observer to a signer of
+	// the claim
+	Distance int `json:"distance"`
+	// Trust is the observer's trust in the claim's most trusted signer,
+	// by path or personalized PageRank; it is 1 for queries without an
+	// observer
+	Trust float64 `json:"trust"`
+	// Freshness is the share of its weight the claim keeps at its age
+	Freshness float64 `json:"freshness"`
+	// Weight is the claim's confidence scaled by Freshness and Trust
+	Weight float64 `json:"weight"`
+	// Opinion is the observer's subjective opinion about the claim's
+	// axiom: the claim's opinion discounted by the trust opinion along the
+	// path to its most trusted signer
+	Opinion subjective.Opinion `json:"opinion"`
 }
 
 // NewNetwork creates a new trust network
 func NewNetwork(logger *logrus.Logger) *Network {
 	return &Network{
 		graph:  graph.NewGraph(logger),
-		claims: make(map[string]*axiom.Claim),
-		logger: logger,
+		claims:  make(map[string]*axiom.Claim),
+		revoked: make(map[revocationKey]*revocationEntry),
+		status:  status.NewList(0),
+		resolver: did.NewRegistry(),
+		now:     time.Now,
+		logger:  logger,
 	}
 }
 
+// SetLog sets the transparency log that accepted claims are appended to
+func (n *Network) SetLog(log *translog.Log) {
+	n.log = log
+}
+
+// SetResolver sets the resolver that binds the issuers and co-signers of
+// accepted claims to their keys, such as axiom.Manager.Resolver
+func (n *Network) SetResolver(resolver did.Resolver) {
+	n.resolver = resolver
+}
+
+// SetRevocationVerifier sets the verifier that checks revocations against
+// the claims they withdraw. Without one, revocations must be signed by the
+// claim's key, its root key or a key bound to its issuer by the resolver.
+func (n *Network) SetRevocationVerifier(verifier RevocationVerifier) {
+	n.revocations = verifier
+}
+
+// SetTimestamper sets the timestamp authority that accepted claims are
+// timestamped by. Claims keep tokens from it or from the trusted
+// authorities, given as did:keys; other tokens are replaced.
+func (n *Network) SetTimestamper(stamper axiom.Stamper, trusted ...string) {
+	n.stamper = stamper
+	n.trustedAuthorities = trusted
+}
+
+// SetHalfLives sets how fast claims lose weight with age in queries,
+// consensus and reputation
+func (n *Network) SetHalfLives(halfLives HalfLives) {
+	n.halfLives = halfLives
+}
+
 // AddClaim adds a new claim to the trust network
 func (n *Network) AddClaim(claim *axiom.Claim) error {
 	n.logger.WithFields(logrus.Fields{
@@ -42,8 +179,79 @@ func (n *Network) AddClaim(claim *axiom.Claim) error {
 		"subject": claim.ClaimBody.Subject,
 	}).Info("Adding claim to trust network")
 
-	// Create graph nodes for issuer and subject
-	issuerNode, err := n.graph.AddNode(claim.Issuer)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Reject unsigned or tampered claims, and claims signed for issuers or
+	// co-signers by keys not bound to them, before they reach the graph
+	if err := axiom.VerifyClaim(context.Background(), n.resolver, claim); err != nil {
+		n.logger.WithError(err).Warn("Rejecting claim with invalid proof")
+		return err
+	}
+	if !axiom.ThresholdMet(claim) {
+		n.logger.WithField("claim_id", axiom.ClaimID(claim)).Warn("Rejecting claim awaiting co-signatures")
+		return axiom.ErrThresholdNotMet
+	}
+
+	// Accepted claims are timestamped once; a claim seen before keeps its
+	// first token
+	previous, seen := n.claims[claim.Proof.ProofValue]
+	if seen && previous.Proof.Timestamp != nil {
+		claim.Proof.Timestamp = previous.Proof.Timestamp
+	}
+	if n.stamper != nil {
+		if err := axiom.Timestamp(context.Background(), claim, n.stamper, n.trustedAuthorities...); err != nil {
+			n.logger.WithError(err).Error("Failed to timestamp claim")
+			return err
+		}
+		// The token bounds the claim's dates, so claims dated after it
+		// are rejected
+		if err := axiom.VerifyProof(claim); err != nil {
+			n.logger.WithError(err).Warn("Rejecting misdated claim")
+			return err
+		}
+	}
+
+	// A revocation that arrived before the claim is checked against it now
+	n.checkRevocation(claim)
+
+	// Claims contradicting their issuer's history are accepted, since
+	// they are validly signed, but flagged as evidence against the issuer
+	if !seen {
+		n.checkChain(claim)
+	}
+
+	// Every accepted claim is recorded in the transparency log
+	if n.log != nil {
+		index, err := n.log.AppendClaim(context.Background(), claim)
+		if err != nil {
+			n.logger.WithError(err).Error("Failed to append claim to transparency log")
+			return err
+		}
+		n.logger.WithField("log_index", index).Debug("Claim logged")
+	}
+
+	// The subject of a private claim is only known to its recipients, so
+	// it adds no edges to the shared graph
+	if claim.IsPrivate() {
+		n.claims[claim.Proof.ProofValue] = claim
+		return nil
+	}
+
+	// A claim seen before may come back with more co-signatures, in any
+	// order; only the signers not seen before get edges, and co-signatures
+	// it comes back without are kept
+	signers := claim.Signers()
+	if previous, ok := n.claims[claim.Proof.ProofValue]; ok {
+		signers = newSigners(previous, claim)
+		if len(signers) == 0 {
+			return nil
+		}
+		claim.Proof.CoSignatures = append(claim.Proof.CoSignatures, missingCoSignatures(previous, claim)...)
+	}
+
+	// A concealed confidence weighs only as much as its disclosure shows
+	disclosed, err := axiom.Disclose(claim)
 	if err != nil {
 		return err
 	}
@@ -53,48 +261,366 @@ func (n *Network) AddClaim(claim *axiom.Claim) error {
 		return err
 	}
 
-	// Create trust edge with confidence weight
-	edge := &graph.Edge{
-		From:   issuerNode,
-		To:     subjectNode,
-		Weight: claim.ClaimBody.Rating.ConfidenceValue,
+	// Co-signers vouch for the claim like its issuer, so each signer gets a
+	// trust edge with the claim's confidence as weight. Equivocation reports
+	// are confident that their subject misbehaved and vouch for no trust.
+	weight := disclosed.ClaimBody.Rating.ConfidenceValue
+	for _, tag := range disclosed.ClaimBody.Tags {
+		if tag == axiom.TagEquivocation {
+			weight = 0
+		}
+	}
+	for _, signer := range signers {
+		signerNode, err := n.graph.AddNode(signer)
+		if err != nil {
+			return err
+		}
+
+		n.graph.AddEdge(signerNode, subjectNode, weight, claim)
 	}
 
-	n.graph.Edges = append(n.graph.Edges, edge)
 	n.claims[claim.Proof.ProofValue] = claim
 
+	// Claims contradicting other signers' claims are accepted too, and
+	// recorded as disputes; the claims this one supersedes dispute no more
+	n.pruneDisputes(claim.ClaimBody.Subject)
+	n.checkDisputes(disclosed)
+	n.updateDisputed(claim.ClaimBody.Subject)
+
+	return nil
+}
+
+// newSigners returns the signers of claim that did not sign previous
+func newSigners(previous, claim *axiom.Claim) []string {
+	known := make(map[string]bool)
+	for _, signer := range previous.Signers() {
+		known[signer] = true
+	}
+
+	signers := make([]string, 0)
+	for _, signer := range claim.Signers() {
+		if !known[signer] {
+			signers = append(signers, signer)
+		}
+	}
+	return signers
+}
+
+// missingCoSignatures returns the co-signatures of previous that claim
+// lacks
+func missingCoSignatures(previous, claim *axiom.Claim) []axiom.CoSignature {
+	present := make(map[string]bool)
+	for _, signer := range claim.Signers() {
+		present[signer] = true
+	}
+
+	missing := make([]axiom.CoSignature, 0)
+	for _, cosignature := range previous.Proof.CoSignatures {
+		if !present[cosignature.Signer] {
+			missing = append(missing, cosignature)
+		}
+	}
+	return missing
+}
+
+// checkChain records the equivocations a new claim reveals
+func (n *Network) checkChain(claim *axiom.Claim) {
+	known := make([]*axiom.Claim, 0)
+	for _, other := range n.claims {
+		if other.Issuer == claim.Issuer {
+			known = append(known, other)
+		}
+	}
+
+	for _, equivocation := range axiom.CheckChain(claim, known) {
+		n.logger.WithFields(logrus.Fields{
+			"issuer":   equivocation.Issuer,
+			"kind":     equivocation.Kind,
+			"sequence": equivocation.Sequence,
+		}).Warn("Issuer equivocated")
+		n.equivocations = append(n.equivocations, equivocation)
+	}
+}
+
+// Equivocations returns the evidence of equivocation in the network: the
+// duplicate sequences and forks seen, followed by the current gaps in
+// issuers' chains
+func (n *Network) Equivocations() []*axiom.Equivocation {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	claims := make([]*axiom.Claim, 0, len(n.claims))
+	for _, claim := range n.claims {
+		claims = append(claims, claim)
+	}
+
+	found := append([]*axiom.Equivocation(nil), n.equivocations...)
+	return append(found, axiom.ChainGaps(claims)...)
+}
+
+// AddRevocation records the revocation of a claim. statusIndex is the
+// claim's entry in the published status list, its transparency log index.
+// A revocation only withdraws the claim of its own issuer with its claim
+// ID, and must be authorized by that issuer; a revocation of a claim not
+// seen yet is checked when the claim arrives.
+func (n *Network) AddRevocation(revocation *axiom.Revocation, statusIndex uint64) error {
+	if err := axiom.VerifyRevocationProof(revocation); err != nil {
+		n.logger.WithError(err).Warn("Rejecting revocation with invalid proof")
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := revocationKey{issuer: revocation.Issuer, claimID: revocation.ClaimID}
+	verified := false
+	for _, claim := range n.claims {
+		if claimKey(claim) != key {
+			continue
+		}
+		if err := n.verifyRevocation(revocation, claim); err != nil {
+			n.logger.WithError(err).Warn("Rejecting unauthorized revocation")
+			return err
+		}
+		verified = true
+	}
+
+	if n.log != nil {
+		if _, err := n.log.AppendRevocation(context.Background(), revocation); err != nil {
+			n.logger.WithError(err).Error("Failed to append revocation to transparency log")
+			return err
+		}
+	}
+
+	n.revoked[key] = &revocationEntry{revocation: revocation, statusIndex: statusIndex, verified: verified}
+	if verified {
+		n.status.Set(statusIndex, true)
+		for _, claim := range n.claims {
+			if claimKey(claim) == key && !claim.IsPrivate() {
+				n.reviewDisputes(claim.ClaimBody.Subject)
+			}
+		}
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"claim_id":     revocation.ClaimID,
+		"status_index": statusIndex,
+	}).Info("Claim revoked")
 	return nil
 }
 
+// IsRevoked reports whether claim has been revoked by its issuer
+func (n *Network) IsRevoked(claim *axiom.Claim) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.isRevoked(claim)
+}
+
+// isRevoked is IsRevoked for callers holding the lock
+func (n *Network) isRevoked(claim *axiom.Claim) bool {
+	entry, ok := n.revoked[claimKey(claim)]
+	return ok && entry.verified
+}
+
+// claimKey returns the key of revocations withdrawing claim
+func claimKey(claim *axiom.Claim) revocationKey {
+	return revocationKey{issuer: claim.Issuer, claimID: axiom.ClaimID(claim)}
+}
+
+// verifyRevocation checks that revocation is authorized by claim's issuer
+func (n *Network) verifyRevocation(revocation *axiom.Revocation, claim *axiom.Claim) error {
+	if n.revocations != nil {
+		return n.revocations.VerifyRevocation(context.Background(), revocation, claim)
+	}
+	return axiom.VerifyRevocation(context.Background(), n.resolver, revocation, claim)
+}
+
+// checkRevocation verifies a revocation recorded before its claim arrived
+// against the claim, and drops it if the claim's issuer did not authorize
+// it
+func (n *Network) checkRevocation(claim *axiom.Claim) {
+	entry, ok := n.revoked[claimKey(claim)]
+	if !ok || entry.verified {
+		return
+	}
+	if err := n.verifyRevocation(entry.revocation, claim); err != nil {
+		n.logger.WithError(err).WithField("claim_id", axiom.ClaimID(claim)).Warn("Dropping unauthorized revocation")
+		delete(n.revoked, claimKey(claim))
+		return
+	}
+	entry.verified = true
+	n.status.Set(entry.statusIndex, true)
+}
+
+// StatusList returns a snapshot of the revocation status list of the
+// network
+func (n *Network) StatusList() *status.List {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.status.Clone()
+}
+
 // Query searches the trust network based on given options
-func (n *Network) Query(opts QueryOptions) ([]*axiom.Claim, error) {
+func (n *Network) Query(opts QueryOptions) ([]*Result, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.query(opts)
+}
+
+// query is Query for callers holding the lock
+func (n *Network) query(opts QueryOptions) ([]*Result, error) {
 	n.logger.WithFields(logrus.Fields{
 		"observer": opts.Observer,
 		"subject":  opts.Subject,
 		"depth":    opts.Depth,
 	}).Info("Querying trust network")
 
-	results := make([]*axiom.Claim, 0)
+	switch opts.Rank {
+	case "", RankPath, RankPPR:
+	case RankFlow:
+		if opts.Observer == "" && len(opts.Seeds) == 0 {
+			return nil, ErrNoFlowSeeds
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRank, opts.Rank)
+	}
 
-	// Implement graph traversal and filtering logic here
-	// This is a simplified version - you'd want to add more sophisticated
-	// graph algorithms for consensus and trust decay
+	results := make([]*Result, 0)
+
+	// From an observer, only claims signed by agents within Depth trust
+	// hops count, weighed by the observer's trust in them
+	var agents map[string]int
+	var trust map[string]float64
+	var opinions map[string]subjective.Opinion
+	if opts.Observer != "" {
+		agents = n.reachableAgents(opts)
+		opinions = n.pathOpinions(opts)
+		switch opts.Rank {
+		case RankPPR:
+			trust = n.pageRankTrust(opts.Observer)
+		case RankFlow:
+			trust = n.flowTrust(opts)
+		default:
+			trust = n.pathTrust(opts)
+		}
+	} else if opts.Rank == RankFlow {
+		// Flow from the seeds alone needs no observer, and its
+		// capacities bound how far it reaches
+		trust = n.flowTrust(opts)
+	}
 
 	for _, claim := range n.claims {
-		if n.matchesQuery(claim, opts) {
-			results = append(results, claim)
+		if !opts.IncludeRevoked && n.isRevoked(claim) {
+			continue
+		}
+		if agents != nil && distance(claim, agents) < 0 {
+			continue
+		}
+		if claim.IsPrivate() {
+			if claim = decrypt(claim, opts.Keys); claim == nil {
+				continue
+			}
+		}
+		if claim.IsSelective() {
+			disclosed, err := axiom.Disclose(claim)
+			if err != nil {
+				continue
+			}
+			claim = disclosed
+		}
+		if !n.matchesQuery(claim, opts) {
+			continue
 		}
+
+		freshness := n.freshness(claim)
+		result := &Result{
+			Claim:     claim,
+			Freshness: freshness,
+			Trust:     1,
+			Opinion:   claimOpinion(claim, freshness),
+		}
+		if agents != nil {
+			result.Opinion = signerOpinion(claim, freshness, opinions)
+			result.Distance = distance(claim, agents)
+		}
+		if trust != nil {
+			result.Trust = 0
+			for _, signer := range claim.Signers() {
+				result.Trust = math.Max(result.Trust, trust[signer])
+			}
+			// Flow bounds what sybils can gain by leaving out every
+			// agent it does not accept
+			if opts.Rank == RankFlow && result.Trust == 0 {
+				continue
+			}
+		}
+		result.Weight = claim.ClaimBody.Rating.ConfidenceValue * freshness * result.Trust
+		results = append(results, result)
+	}
+
+	// Closest agents first, or the heaviest claims when trust decays or
+	// is ranked by PageRank
+	if agents != nil {
+		sort.SliceStable(results, func(i, j int) bool {
+			if opts.UseTrustDecay || opts.Rank == RankPPR {
+				return results[i].Weight > results[j].Weight
+			}
+			return results[i].Distance < results[j].Distance
+		})
 	}
 
 	return results, nil
 }
 
+// reachableAgents walks the trust graph from the observer and returns the
+// hop distance of every agent within opts.Depth hops. Edges are followed
+// if they carry positive trust from a claim that is not revoked.
+func (n *Network) reachableAgents(opts QueryOptions) map[string]int {
+	agents := map[string]int{opts.Observer: 0}
+	observer, ok := n.graph.Lookup(opts.Observer)
+	if !ok {
+		return agents
+	}
+
+	follow := func(edge *graph.Edge) bool {
+		claim, ok := edge.Data.(*axiom.Claim)
+		if !ok || edge.Weight <= 0 {
+			return false
+		}
+		return opts.IncludeRevoked || !n.isRevoked(claim)
+	}
+	for node, hops := range n.graph.Reachable(observer, opts.Depth, follow) {
+		if agent, ok := node.Data.(string); ok {
+			agents[agent] = hops
+		}
+	}
+	return agents
+}
+
+// distance returns the smallest hop distance of the claim's signers, or -1
+// if none was reached
+func distance(claim *axiom.Claim, agents map[string]int) int {
+	closest := -1
+	for _, signer := range claim.Signers() {
+		if hops, ok := agents[signer]; ok && (closest < 0 || hops < closest) {
+			closest = hops
+		}
+	}
+	return closest
+}
+
 func (n *Network) matchesQuery(claim *axiom.Claim, opts QueryOptions) bool {
 	// Implement filtering logic based on QueryOptions
 	if opts.Subject != "" && claim.ClaimBody.Subject != opts.Subject {
 		return false
 	}
-	if opts.Agent != "" && claim.Issuer != opts.Agent {
+	if opts.Agent != "" && !signedBy(claim, opts.Agent) {
+		return false
+	}
+	if opts.Axiom != "" && claim.ClaimBody.Rating.Axiom != opts.Axiom {
+		return false
+	}
+	if len(opts.Tags) > 0 && !taggedAny(claim, opts.Tags) {
 		return false
 	}
 	if claim.ClaimBody.Rating.ConfidenceValue < opts.MinConfidence ||
@@ -103,4 +629,66 @@ func (n *Network) matchesQuery(claim *axiom.Claim, opts QueryOptions) bool {
 	}
 	// Add more filtering conditions as needed
 	return true
-} 
\ No newline at end of file
+} 
+
+// HasPrivateClaims reports whether a query with opts could find private
+// claims addressed to recipient, a did:key: unrevoked claims, unless
+// revoked ones are included, signed by agents the observer reaches.
+// Subjects and other body fields are encrypted, so they are not matched.
+func (n *Network) HasPrivateClaims(opts QueryOptions, recipient string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var agents map[string]int
+	if opts.Observer != "" {
+		agents = n.reachableAgents(opts)
+	}
+	for _, claim := range n.claims {
+		if !claim.IsRecipient(recipient) {
+			continue
+		}
+		if !opts.IncludeRevoked && n.isRevoked(claim) {
+			continue
+		}
+		if agents != nil && distance(claim, agents) < 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// decrypt opens a private claim with the first key that is one of its
+// recipients, or returns nil
+func decrypt(claim *axiom.Claim, keys []*crypto.KeyPair) *axiom.Claim {
+	for _, key := range keys {
+		if opened, err := axiom.Decrypt(claim, key); err == nil {
+			return opened
+		}
+	}
+	return nil
+}
+
+// taggedAny reports whether the claim shows one of the tags. Claims
+// concealing their tags match none.
+func taggedAny(claim *axiom.Claim, tags []string) bool {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		for _, claimTag := range claim.ClaimBody.Tags {
+			if claimTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// signedBy reports whether agent issued or co-signed the claim
+func signedBy(claim *axiom.Claim, agent string) bool {
+	for _, signer := range claim.Signers() {
+		if signer == agent {
+			return true
+		}
+	}
+	return false
+}
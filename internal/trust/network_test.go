@@ -0,0 +1,618 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"axia/internal/axiom"
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"axia/internal/timestamp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestNetwork returns a network and a manager able to sign for agents
+func newTestNetwork(t *testing.T, agents ...string) (*Network, *axiom.Manager) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	manager := axiom.NewManager(logger)
+	for _, agent := range agents {
+		key, err := crypto.GenerateKeyPair()
+		assert.NoError(t, err)
+		manager.RegisterSigner(agent, key)
+	}
+	network := NewNetwork(logger)
+	network.SetResolver(manager.Resolver())
+	return network, manager
+}
+
+func addClaim(t *testing.T, network *Network, manager *axiom.Manager, agent, subject string, confidence float64) *axiom.Claim {
+	claim, err := manager.CreateClaim(agent, subject, "", confidence, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, network.AddClaim(claim))
+	return claim
+}
+
+func issuers(results []*Result) []string {
+	found := make([]string, 0, len(results))
+	for _, result := range results {
+		found = append(found, result.Claim.Issuer)
+	}
+	return found
+}
+
+func TestAddClaimBindsSigners(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob")
+
+	// A key not bound to alice cannot make claims as alice
+	mallory, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	other := axiom.NewManager(network.logger)
+	other.RegisterSigner("alice", mallory)
+	other.RegisterSigner("bob", mallory)
+	forged, err := other.CreateClaim("alice", "carol", "", 0.9, nil)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, network.AddClaim(forged), did.ErrKeyNotAuthorized)
+
+	// Nor co-sign as bob to meet a threshold or vouch for the subject
+	policy := &axiom.ThresholdPolicy{Threshold: 2, Signers: []string{"alice", "bob"}}
+	claim, err := manager.CreateCoSignedClaim("alice", "carol", "", 0.9, nil, policy)
+	assert.NoError(t, err)
+	assert.NoError(t, other.CoSign(claim, "bob"))
+	assert.ErrorIs(t, network.AddClaim(claim), axiom.ErrCoSignatureBroken)
+	_, ok := network.graph.Lookup("bob")
+	assert.False(t, ok)
+
+	claim.Proof.CoSignatures = nil
+	assert.NoError(t, manager.CoSign(claim, "bob"))
+	assert.NoError(t, network.AddClaim(claim))
+	_, ok = network.graph.Lookup("bob")
+	assert.True(t, ok)
+}
+
+func TestResubmittedCoSignatures(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave")
+	policy := &axiom.ThresholdPolicy{Threshold: 2, Signers: []string{"alice", "bob", "carol", "dave"}}
+	claim, err := manager.CreateCoSignedClaim("alice", "erin", "", 0.9, nil, policy)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.CoSign(claim, "bob"))
+	assert.NoError(t, network.AddClaim(claim))
+
+	resubmit := func(cosigner string, known ...axiom.CoSignature) *axiom.Claim {
+		resubmitted := *claim
+		resubmitted.Proof.CoSignatures = nil
+		assert.NoError(t, manager.CoSign(&resubmitted, cosigner))
+		resubmitted.Proof.CoSignatures = append(resubmitted.Proof.CoSignatures, known...)
+		assert.NoError(t, network.AddClaim(&resubmitted))
+		return &resubmitted
+	}
+
+	// The claim comes back with carol's co-signature ahead of bob's
+	resubmit("carol", claim.Proof.CoSignatures...)
+	for _, signer := range []string{"alice", "bob", "carol"} {
+		node, ok := network.graph.Lookup(signer)
+		assert.True(t, ok, signer)
+		assert.Len(t, network.graph.Outgoing(node), 1, signer)
+	}
+
+	// Co-signatures it comes back without are kept
+	resubmit("dave")
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol", "dave"}, network.claims[claim.Proof.ProofValue].Signers())
+}
+
+func TestRevocationsWithdrawOnlyTheirIssuersClaims(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob")
+	claim, err := manager.CreateClaim("alice", "carol", "", 0.9, nil)
+	assert.NoError(t, err)
+
+	// Revocations under another issuer or by another key, before or after
+	// the claim arrives, leave it alone
+	impostor := *claim
+	impostor.Issuer = "bob"
+	other, err := manager.RevokeClaim(&impostor, "")
+	assert.NoError(t, err)
+	assert.NoError(t, network.AddRevocation(other, 0))
+
+	mallory := axiom.NewManager(network.logger)
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	mallory.RegisterSigner("alice", key)
+	forged, err := mallory.RevokeClaim(claim, "")
+	assert.NoError(t, err)
+	assert.NoError(t, network.AddRevocation(forged, 1))
+
+	assert.NoError(t, network.AddClaim(claim))
+	assert.False(t, network.IsRevoked(claim))
+	listed, _ := network.StatusList().Get(1)
+	assert.False(t, listed)
+	assert.ErrorIs(t, network.AddRevocation(forged, 1), axiom.ErrRevocationKey)
+
+	revocation, err := manager.RevokeClaim(claim, "")
+	assert.NoError(t, err)
+	assert.NoError(t, network.AddRevocation(revocation, 1))
+	assert.True(t, network.IsRevoked(claim))
+	listed, _ = network.StatusList().Get(1)
+	assert.True(t, listed)
+}
+
+func TestAddClaimTimestamps(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice")
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	authority, err := timestamp.NewAuthority(context.Background(), timestamp.NewMemoryStorage(), key, network.logger)
+	assert.NoError(t, err)
+	network.SetTimestamper(authority)
+
+	// A token from an authority of the issuer's own is replaced
+	own, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	backdater, err := timestamp.NewAuthority(context.Background(), timestamp.NewMemoryStorage(), own, network.logger)
+	assert.NoError(t, err)
+	claim, err := manager.CreateClaim("alice", "bob", "", 0.9, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, axiom.Timestamp(context.Background(), claim, backdater))
+	assert.NoError(t, network.AddClaim(claim))
+	assert.Equal(t, authority.ID(), claim.Proof.Timestamp.Authority)
+	assert.NoError(t, axiom.RequireTimestamp(claim, []string{authority.ID()}))
+}
+
+func TestConcurrentAddAndQuery(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob")
+	claims := make([]*axiom.Claim, 0, 40)
+	for i := 0; i < cap(claims); i++ {
+		claim, err := manager.CreateClaim("alice", fmt.Sprintf("agent-%d", i), "", 0.9, nil)
+		assert.NoError(t, err)
+		claims = append(claims, claim)
+	}
+
+	// Run with -race: queries read the graph while claims extend it
+	var wg sync.WaitGroup
+	for _, claim := range claims {
+		wg.Add(2)
+		go func(claim *axiom.Claim) {
+			defer wg.Done()
+			assert.NoError(t, network.AddClaim(claim))
+		}(claim)
+		go func() {
+			defer wg.Done()
+			_, err := network.Query(QueryOptions{Observer: "alice", Depth: 2, MaxConfidence: 1})
+			assert.NoError(t, err)
+			network.PersonalizedReputation("alice")
+			network.StatusList()
+		}()
+	}
+	wg.Wait()
+
+	results, err := network.Query(QueryOptions{Observer: "alice", Depth: 1, MaxConfidence: 1})
+	assert.NoError(t, err)
+	assert.Len(t, results, len(claims))
+}
+
+func TestQueryTraversesFromObserver(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "mallory")
+
+	// alice -> bob -> carol, and alice distrusts mallory
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "bob", "carol", 0.8)
+	addClaim(t, network, manager, "alice", "mallory", 0)
+	for _, agent := range []string{"carol", "bob", "mallory"} {
+		addClaim(t, network, manager, agent, "book:accelerando", 0.7)
+	}
+
+	query := func(depth int) []string {
+		results, err := network.Query(QueryOptions{
+			Observer:      "alice",
+			Subject:       "book:accelerando",
+			Depth:         depth,
+			MaxConfidence: 1,
+		})
+		assert.NoError(t, err)
+		return issuers(results)
+	}
+
+	assert.Empty(t, query(0))
+	assert.Equal(t, []string{"bob"}, query(1))
+	assert.Equal(t, []string{"bob", "carol"}, query(2))
+
+	// Without an observer the whole network is searched
+	results, err := network.Query(QueryOptions{Subject: "book:accelerando", MaxConfidence: 1})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"bob", "carol", "mallory"}, issuers(results))
+}
+
+func TestQueryFiltersTags(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol")
+	for agent, tags := range map[string][]string{
+		"alice": {"security", "audit"},
+		"bob":   {"market"},
+		"carol": nil,
+	} {
+		claim, err := manager.CreateClaim(agent, "token:xyz", "", 0.7, tags)
+		assert.NoError(t, err)
+		assert.NoError(t, network.AddClaim(claim))
+	}
+
+	query := func(tags ...string) []string {
+		results, err := network.Query(QueryOptions{Subject: "token:xyz", Tags: tags, MaxConfidence: 1})
+		assert.NoError(t, err)
+		return issuers(results)
+	}
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol"}, query())
+	assert.Equal(t, []string{"alice"}, query("audit"))
+	assert.ElementsMatch(t, []string{"alice", "bob"}, query("security", " market"))
+	assert.Empty(t, query("rug"))
+}
+
+func TestQueryTrustDecay(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol")
+
+	// carol is reached directly with little trust, or through bob
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "bob", "carol", 0.8)
+	addClaim(t, network, manager, "alice", "carol", 0.5)
+	addClaim(t, network, manager, "carol", "book:accelerando", 1)
+
+	query := func(opts QueryOptions) *Result {
+		opts.Observer, opts.Subject, opts.Depth, opts.MaxConfidence = "alice", "book:accelerando", 3, 1
+		results, err := network.Query(opts)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		return results[0]
+	}
+
+	// Without decay the longer path through bob carries more trust
+	result := query(QueryOptions{})
+	assert.Equal(t, 1, result.Distance)
+	assert.InDelta(t, 0.72, result.Trust, 1e-9)
+	assert.InDelta(t, 0.72, result.Weight, 1e-9)
+
+	assert.InDelta(t, 0.8, query(QueryOptions{TNorm: Minimum}).Trust, 1e-9)
+	assert.InDelta(t, 0.7, query(QueryOptions{TNorm: Lukasiewicz}).Trust, 1e-9)
+
+	// Decay favours the direct edge: 0.5 * 0.5 beats 0.72 * 0.25
+	result = query(QueryOptions{UseTrustDecay: true, Decay: ExponentialDecay{Rate: 0.5}})
+	assert.InDelta(t, 0.25, result.Trust, 1e-9)
+
+	result = query(QueryOptions{UseTrustDecay: true, Decay: CutoffDecay{MaxHops: 1}})
+	assert.InDelta(t, 0.5, result.Trust, 1e-9)
+
+	result = query(QueryOptions{UseTrustDecay: true, Decay: LinearDecay{MaxHops: 3}})
+	assert.InDelta(t, 0.375, result.Trust, 1e-9)
+}
+
+func TestConsensus(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave")
+
+	_, err := network.Consensus(QueryOptions{MaxConfidence: 1})
+	assert.ErrorIs(t, err, ErrNoConsensusTopic)
+
+	// bob changes his mind; only his latest claim counts
+	addClaim(t, network, manager, "bob", "book:accelerando", 0.2)
+	addClaim(t, network, manager, "bob", "book:accelerando", 0.9)
+	addClaim(t, network, manager, "carol", "book:accelerando", 0.8)
+	addClaim(t, network, manager, "dave", "book:accelerando", 0.1)
+
+	report, err := network.Consensus(QueryOptions{Subject: "book:accelerando", MaxConfidence: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Issuers)
+	assert.InDelta(t, 0.6, report.Mean, 1e-9)
+	assert.InDelta(t, 0.38/3, report.Variance, 1e-9)
+	assert.Len(t, report.Clusters, 2)
+	assert.ElementsMatch(t, []string{"bob", "carol"}, report.Clusters[0].Issuers)
+	assert.InDelta(t, 0.85, report.Clusters[0].Confidence, 1e-9)
+	assert.InDelta(t, 2.0/3, report.Clusters[0].Weight, 1e-9)
+	assert.InDelta(t, (1-4*0.38/3)*0.75, report.Score, 1e-9)
+
+	// From alice, who only trusts bob, bob's view is the consensus
+	addClaim(t, network, manager, "alice", "bob", 1)
+	report, err = network.Consensus(QueryOptions{Observer: "alice", Subject: "book:accelerando", Depth: 2, MaxConfidence: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Issuers)
+	assert.InDelta(t, 0.9, report.Mean, 1e-9)
+}
+
+func TestGlobalReputation(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "sybil1", "sybil2")
+
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "alice", "carol", 0.6)
+	addClaim(t, network, manager, "bob", "carol", 0.8)
+	addClaim(t, network, manager, "carol", "alice", 0.7)
+
+	// Sybils vouching for each other gain nothing without trust from the
+	// pretrusted agents
+	addClaim(t, network, manager, "sybil1", "sybil2", 1)
+	addClaim(t, network, manager, "sybil2", "sybil1", 1)
+
+	scores := make(map[string]float64)
+	var total float64
+	for _, reputation := range network.GlobalReputation("alice") {
+		scores[reputation.Agent] = reputation.Score
+		total += reputation.Score
+	}
+	assert.InDelta(t, 1, total, 1e-6)
+	assert.Greater(t, scores["carol"], scores["bob"])
+	assert.InDelta(t, 0, scores["sybil1"], 1e-9)
+	assert.InDelta(t, 0, scores["sybil2"], 1e-9)
+
+	// Only the latest claim counts: bob withdraws their trust in carol
+	addClaim(t, network, manager, "bob", "carol", 0)
+	withdrawn := network.GlobalReputation("alice")
+	for _, reputation := range withdrawn {
+		if reputation.Agent == "carol" {
+			assert.Less(t, reputation.Score, scores["carol"])
+		}
+	}
+}
+
+func TestPersonalizedPageRank(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave", "mallory")
+
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "alice", "carol", 0.3)
+	addClaim(t, network, manager, "bob", "dave", 0.8)
+	addClaim(t, network, manager, "mallory", "dave", 1)
+	for _, agent := range []string{"carol", "bob", "mallory"} {
+		addClaim(t, network, manager, agent, "book:accelerando", 0.7)
+	}
+
+	// mallory is not reachable from alice and is left out
+	agents := make([]string, 0)
+	for _, reputation := range network.PersonalizedReputation("alice") {
+		agents = append(agents, reputation.Agent)
+	}
+	assert.NotContains(t, agents, "mallory")
+	assert.Equal(t, []string{"alice", "bob"}, agents[:2])
+
+	results, err := network.Query(QueryOptions{
+		Observer:      "alice",
+		Subject:       "book:accelerando",
+		Depth:         3,
+		MaxConfidence: 1,
+		Rank:          RankPPR,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bob", "carol"}, issuers(results))
+	assert.Greater(t, results[0].Trust, results[1].Trust)
+
+	_, err = network.Query(QueryOptions{Observer: "alice", Rank: "hits"})
+	assert.ErrorIs(t, err, ErrUnknownRank)
+}
+
+func TestQueryOpinions(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave")
+
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "alice", "carol", 0.9)
+	addClaim(t, network, manager, "alice", "dave", 0.2)
+	for _, agent := range []string{"bob", "carol", "dave"} {
+		addClaim(t, network, manager, agent, "book:accelerando", 0.8)
+	}
+
+	opts := QueryOptions{
+		Observer:      "alice",
+		Subject:       "book:accelerando",
+		Depth:         1,
+		MaxConfidence: 1,
+	}
+	results, err := network.Query(opts)
+	assert.NoError(t, err)
+	opinions := make(map[string]float64)
+	for _, result := range results {
+		assert.NoError(t, result.Opinion.Validate())
+		opinions[result.Claim.Issuer] = result.Opinion.Uncertainty
+	}
+	// Distrust in dave leaves their claim uncertain rather than false
+	assert.Greater(t, opinions["dave"], opinions["bob"])
+
+	// Independent issuers add up to more certainty than any one of them
+	report, err := network.Consensus(opts)
+	assert.NoError(t, err)
+	assert.Less(t, report.Opinion.Uncertainty, opinions["bob"])
+
+	opts.Fusion = "majority"
+	_, err = network.Consensus(opts)
+	assert.Error(t, err)
+}
+
+func TestFlowTrustBoundsSybils(t *testing.T) {
+	sybils := []string{"sybil1", "sybil2", "sybil3", "sybil4", "sybil5", "sybil6"}
+	network, manager := newTestNetwork(t, append([]string{"alice", "bob", "carol", "mallory"}, sybils...)...)
+
+	addClaim(t, network, manager, "alice", "bob", 0.9)
+	addClaim(t, network, manager, "alice", "carol", 0.8)
+	addClaim(t, network, manager, "alice", "dave", 0.3)
+	// A single edge from bob leads into mallory's sybils, who all vouch
+	// for each other
+	addClaim(t, network, manager, "bob", "mallory", 0.9)
+	for _, sybil := range sybils {
+		addClaim(t, network, manager, "mallory", sybil, 1)
+		for _, other := range sybils {
+			if other != sybil {
+				addClaim(t, network, manager, sybil, other, 1)
+			}
+		}
+	}
+	for _, agent := range append([]string{"bob", "carol", "mallory"}, sybils...) {
+		addClaim(t, network, manager, agent, "book:accelerando", 0.7)
+	}
+
+	results, err := network.Query(QueryOptions{
+		Observer:      "alice",
+		Subject:       "book:accelerando",
+		Depth:         4,
+		MaxConfidence: 1,
+		Rank:          RankFlow,
+		Capacities:    []int{6, 3, 3, 3, 3},
+	})
+	assert.NoError(t, err)
+
+	found := issuers(results)
+	assert.Subset(t, found, []string{"bob", "carol", "mallory"})
+	accepted := 0
+	for _, sybil := range sybils {
+		for _, issuer := range found {
+			if issuer == sybil {
+				accepted++
+			}
+		}
+	}
+	// bob passes mallory at most 2 units of flow, one of which mallory
+	// keeps
+	assert.LessOrEqual(t, accepted, 1)
+	assert.Len(t, found, 3+accepted)
+
+	// Seeds rank without an observer, and flow needs one or the other
+	results, err = network.Query(QueryOptions{
+		Subject:       "book:accelerando",
+		MaxConfidence: 1,
+		Rank:          RankFlow,
+		Seeds:         []string{"carol"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"carol"}, issuers(results))
+	_, err = network.Query(QueryOptions{Subject: "book:accelerando", MaxConfidence: 1, Rank: RankFlow})
+	assert.ErrorIs(t, err, ErrNoFlowSeeds)
+}
+
+func TestDisputes(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave")
+
+	rate := func(agent, statement string, confidence float64) *axiom.Claim {
+		claim, err := manager.CreateClaim(agent, "contract:0xabc", statement, confidence, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, network.AddClaim(claim))
+		return claim
+	}
+
+	rate("alice", "The contract is safe.", 0.9)
+	assert.False(t, network.IsDisputed("contract:0xabc"))
+
+	// Explicit negation, and a far lower rating of the same axiom
+	rate("bob", "the contract is NOT safe", 0.9)
+	rate("carol", "The contract is safe", 0.2)
+	// Agreeing with alice in other words is no dispute, but disputes bob
+	// and carol
+	rate("dave", "the contract isn't safe", 0.1)
+
+	disputes := network.Disputes()
+	kinds := make([]string, 0)
+	for _, dispute := range disputes {
+		assert.Equal(t, "the contract is safe", dispute.Axiom)
+		kinds = append(kinds, dispute.Kind)
+	}
+	assert.Equal(t, []string{DisputeNegated, DisputeDivergent, DisputeDivergent, DisputeNegated}, kinds)
+	assert.True(t, network.IsDisputed("contract:0xabc"))
+
+	conflicts, err := network.Conflicts(QueryOptions{Agent: "alice", Axiom: "The contract is safe", MaxConfidence: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	conflicts, err = network.Conflicts(QueryOptions{Subject: "contract:0xabc", Axiom: "contract is safe"})
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	conflicts, err = network.Conflicts(QueryOptions{Subject: "contract:0xabc", Axiom: "The contract is safe!"})
+	assert.NoError(t, err)
+	assert.Len(t, conflicts, len(disputes))
+}
+
+func TestDisputesAreWithdrawn(t *testing.T) {
+	network, manager := newTestNetwork(t, "alice", "bob", "carol", "dave")
+
+	rate := func(agent, subject string, confidence float64, cosigners ...string) *axiom.Claim {
+		claim, err := manager.CreateClaim(agent, subject, "The token is safe", confidence, nil)
+		assert.NoError(t, err)
+		for _, cosigner := range cosigners {
+			assert.NoError(t, manager.CoSign(claim, cosigner))
+		}
+		assert.NoError(t, network.AddClaim(claim))
+		return claim
+	}
+
+	// A superseded claim disputes no more
+	rate("alice", "token:abc", 0.1)
+	rate("dave", "token:abc", 0.9)
+	assert.True(t, network.IsDisputed("token:abc"))
+	rate("alice", "token:abc", 0.8)
+	assert.Empty(t, network.Disputes())
+	assert.False(t, network.IsDisputed("token:abc"))
+
+	// A co-signed claim is disputed once, and stands while a co-signer
+	// holds it
+	safe := rate("alice", "token:xyz", 0.9)
+	rate("bob", "token:xyz", 0.2, "carol")
+	assert.Len(t, network.Disputes(), 1)
+	rate("bob", "token:xyz", 0.9)
+	assert.Len(t, network.Disputes(), 2)
+
+	// Revoking a claim withdraws its disputes
+	revocation, err := manager.RevokeClaim(safe, "")
+	assert.NoError(t, err)
+	assert.NoError(t, network.AddRevocation(revocation, 0))
+	disputes := network.Disputes()
+	assert.Len(t, disputes, 1)
+	for _, claim := range disputes[0].Claims {
+		assert.NotEqual(t, safe.Proof.ProofValue, claim.Proof.ProofValue)
+	}
+	assert.True(t, network.IsDisputed("token:xyz"))
+}
+
+func TestClaimAging(t *testing.T) {
+	halfLives, err := ParseHalfLives("365d", "security=730d, market=168h")
+	assert.NoError(t, err)
+	assert.Equal(t, 365*24*time.Hour, halfLives.Default)
+	assert.Equal(t, 7*24*time.Hour, halfLives.Tags["market"])
+	_, err = ParseHalfLives("soon", "")
+	assert.ErrorIs(t, err, ErrInvalidHalfLife)
+
+	network, manager := newTestNetwork(t, "alice", "bob", "carol")
+	network.SetHalfLives(halfLives)
+	rate := func(agent, subject, tag string, confidence float64) {
+		claim, err := manager.CreateClaim(agent, subject, "", confidence, []string{tag})
+		assert.NoError(t, err)
+		assert.NoError(t, network.AddClaim(claim))
+	}
+	rate("alice", "bob", "security", 0.9)
+	rate("alice", "carol", "market", 0.9)
+	rate("bob", "token:xyz", "security", 0.9)
+	rate("carol", "token:xyz", "market", 0.1)
+
+	// Two weeks on, market claims have lost three quarters of their weight
+	network.now = func() time.Time { return time.Now().Add(14 * 24 * time.Hour) }
+
+	results, err := network.Query(QueryOptions{Subject: "token:xyz", MaxConfidence: 1})
+	assert.NoError(t, err)
+	for _, result := range results {
+		if result.Claim.Issuer == "carol" {
+			assert.InDelta(t, 0.25, result.Freshness, 1e-3)
+			assert.InDelta(t, 0.025, result.Weight, 1e-3)
+		} else {
+			assert.InDelta(t, 0.987, result.Freshness, 1e-3)
+		}
+	}
+
+	report, err := network.Consensus(QueryOptions{Subject: "token:xyz", MaxConfidence: 1})
+	assert.NoError(t, err)
+	// bob's fresh claim outweighs carol's four to one
+	assert.Equal(t, []string{"bob"}, report.Clusters[0].Issuers)
+	assert.InDelta(t, 0.8, report.Clusters[0].Weight, 1e-2)
+
+	scores := make(map[string]float64)
+	for _, reputation := range network.GlobalReputation("alice") {
+		scores[reputation.Agent] = reputation.Score
+	}
+	assert.Greater(t, scores["bob"], 3*scores["carol"])
+
+	// Claims age from their timestamp if it is earlier than their date, and
+	// claims dated in the future keep no weight
+	now := time.Now()
+	stamped := &axiom.Claim{Issued: now, Proof: axiom.Proof{Timestamp: &timestamp.Token{Time: now.Add(-365 * 24 * time.Hour)}}}
+	assert.InDelta(t, 0.5, halfLives.Freshness(stamped, now), 1e-3)
+	assert.Equal(t, 0.0, halfLives.Freshness(&axiom.Claim{Issued: now.Add(time.Hour)}, now))
+	assert.Equal(t, 1.0, halfLives.Freshness(&axiom.Claim{Issued: now.Add(time.Minute)}, now))
+}
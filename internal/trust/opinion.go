@@ -0,0 +1,93 @@
+package trust
+
+import (
+	"axia/internal/axiom"
+	"axia/internal/graph"
+	"axia/internal/subjective"
+)
+
+// ClaimEvidence is the amount of evidence a single claim stands for. A
+// claim of confidence c is read as c·ClaimEvidence positive and
+// (1-c)·ClaimEvidence negative observations, which leaves an uncertainty
+// of 0.2.
+const ClaimEvidence = 8
+
+// claimOpinion returns the opinion a claim expresses about its axiom. Its
+// evidence fades with the claim's freshness, and a concealed confidence
+// expresses no opinion at all.
+func claimOpinion(claim *axiom.Claim, freshness float64) subjective.Opinion {
+	if !axiom.Reveals(claim, axiom.DisclosableConfidence) {
+		return subjective.Vacuous(subjective.DefaultBaseRate)
+	}
+	return subjective.FromConfidence(claim.ClaimBody.Rating.ConfidenceValue, ClaimEvidence*freshness)
+}
+
+// edgeOpinion returns the opinion of a trust edge's claim. The edge
+// weight is the claim's disclosed confidence.
+func (n *Network) edgeOpinion(edge *graph.Edge, claim *axiom.Claim) subjective.Opinion {
+	if !axiom.Reveals(claim, axiom.DisclosableConfidence) {
+		return subjective.Vacuous(subjective.DefaultBaseRate)
+	}
+	return subjective.FromConfidence(edge.Weight, ClaimEvidence*n.freshness(claim))
+}
+
+// pathOpinions returns the observer's trust opinion about every agent
+// within opts.Depth hops. Each claim is an opinion about its subject, and
+// the opinions along a path are discounted by one another; of several
+// paths, the one with the highest expectation counts.
+func (n *Network) pathOpinions(opts QueryOptions) map[string]subjective.Opinion {
+	self := subjective.Opinion{Belief: 1, BaseRate: subjective.DefaultBaseRate}
+	opinions := map[string]subjective.Opinion{opts.Observer: self}
+	observer, ok := n.graph.Lookup(opts.Observer)
+	if !ok {
+		return opinions
+	}
+
+	best := map[*graph.Node]subjective.Opinion{observer: self}
+	layer := map[*graph.Node]subjective.Opinion{observer: self}
+	for hop := 1; hop <= opts.Depth && len(layer) > 0; hop++ {
+		next := make(map[*graph.Node]subjective.Opinion)
+		for node, trust := range layer {
+			for _, edge := range n.graph.Outgoing(node) {
+				claim, ok := edge.Data.(*axiom.Claim)
+				if !ok || (!opts.IncludeRevoked && n.isRevoked(claim)) {
+					continue
+				}
+				derived := trust.Discount(n.edgeOpinion(edge, claim))
+				if previous, ok := best[edge.To]; ok && derived.Expectation() <= previous.Expectation() {
+					continue
+				}
+				if previous, ok := next[edge.To]; ok && derived.Expectation() <= previous.Expectation() {
+					continue
+				}
+				next[edge.To] = derived
+			}
+		}
+
+		for node, derived := range next {
+			best[node] = derived
+			if agent, ok := node.Data.(string); ok {
+				opinions[agent] = derived
+			}
+		}
+		layer = next
+	}
+	return opinions
+}
+
+// signerOpinion returns the observer's opinion about the claim's axiom
+// through its most trusted signer
+func signerOpinion(claim *axiom.Claim, freshness float64, trust map[string]subjective.Opinion) subjective.Opinion {
+	opinion := subjective.Vacuous(subjective.DefaultBaseRate)
+	var best *subjective.Opinion
+	for _, signer := range claim.Signers() {
+		t, ok := trust[signer]
+		if ok && (best == nil || t.Expectation() > best.Expectation()) {
+			best = &t
+		}
+	}
+	if best != nil {
+		opinion = best.Discount(claimOpinion(claim, freshness))
+	}
+	return opinion
+}
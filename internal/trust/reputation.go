@@ -0,0 +1,148 @@
+package trust
+
+import (
+	"errors"
+	"sort"
+
+	"axia/internal/axiom"
+	"axia/internal/graph"
+)
+
+// Ranking modes weighing query results from the observer's view
+const (
+	// RankPath trusts agents as much as the best path to them
+	RankPath = "path"
+	// RankPPR trusts agents by their personalized PageRank from the
+	// observer
+	RankPPR = "ppr"
+	// RankFlow fully trusts the agents the Advogato flow metric accepts
+	// from the seeds and leaves out all others
+	RankFlow = "flow"
+)
+
+var (
+	ErrUnknownRank = errors.New("unknown ranking mode")
+	ErrNoFlowSeeds = errors.New("flow ranking needs an observer or seeds")
+)
+
+// Reputation is the global trust the network places in an agent
+type Reputation struct {
+	Agent string  `json:"agent"`
+	Score float64 `json:"score"`
+}
+
+// GlobalReputation ranks every agent and subject of the network by
+// EigenTrust, highest first. Trust restarts at the pretrusted agents, which
+// keeps colluding agents from vouching each other up; without any, every
+// agent is pretrusted equally. Each signer's latest unrevoked claim about
+// a subject is its local trust in it.
+func (n *Network) GlobalReputation(pretrusted ...string) []*Reputation {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	params := graph.EigenTrustParams{
+		PretrustWeight: graph.DefaultPretrustWeight,
+		Tolerance:      graph.DefaultTolerance,
+		MaxIterations:  graph.DefaultMaxIterations,
+		Follow:         n.followLatest(),
+		Weigh:          n.edgeWeight,
+	}
+	for _, agent := range pretrusted {
+		if node, ok := n.graph.Lookup(agent); ok {
+			params.Pretrusted = append(params.Pretrusted, node)
+		}
+	}
+	return rank(n.graph.EigenTrust(params))
+}
+
+// PersonalizedReputation ranks the agents and subjects of the network by
+// personalized PageRank from the observer, highest first: the chance that
+// a walk along trust edges, restarting at the observer, is found at each.
+// Agents the observer cannot reach are left out.
+func (n *Network) PersonalizedReputation(observer string) []*Reputation {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	reputations := make([]*Reputation, 0)
+	for _, reputation := range rank(n.personalizedPageRank(observer)) {
+		if reputation.Score > 0 {
+			reputations = append(reputations, reputation)
+		}
+	}
+	return reputations
+}
+
+// personalizedPageRank walks each signer's latest unrevoked opinions from
+// the observer
+func (n *Network) personalizedPageRank(observer string) map[*graph.Node]float64 {
+	source, _ := n.graph.Lookup(observer)
+	return n.graph.PersonalizedPageRank(source, graph.DefaultRestartProbability, n.followLatest(), n.edgeWeight)
+}
+
+// pageRankTrust returns the observer's trust in every agent by personalized
+// PageRank
+func (n *Network) pageRankTrust(observer string) map[string]float64 {
+	trust := make(map[string]float64)
+	for node, score := range n.personalizedPageRank(observer) {
+		if agent, ok := node.Data.(string); ok {
+			trust[agent] = score
+		}
+	}
+	return trust
+}
+
+// rank sorts scored nodes into reputations, highest first
+func rank(scores map[*graph.Node]float64) []*Reputation {
+	reputations := make([]*Reputation, 0, len(scores))
+	for node, score := range scores {
+		if agent, ok := node.Data.(string); ok {
+			reputations = append(reputations, &Reputation{Agent: agent, Score: score})
+		}
+	}
+	sort.Slice(reputations, func(i, j int) bool {
+		if reputations[i].Score != reputations[j].Score {
+			return reputations[i].Score > reputations[j].Score
+		}
+		return reputations[i].Agent < reputations[j].Agent
+	})
+	return reputations
+}
+
+// followLatest selects the edges of each signer's latest unrevoked claim
+// about a subject, so that repeating a claim adds no weight
+func (n *Network) followLatest() func(*graph.Edge) bool {
+	latest := n.latestOpinions()
+	return func(edge *graph.Edge) bool {
+		claim, ok := edge.Data.(*axiom.Claim)
+		if !ok {
+			return false
+		}
+		signer, _ := edge.From.Data.(string)
+		current, ok := latest[opinionKey{signer, claim.ClaimBody.Subject}]
+		return ok && current.Proof.ProofValue == claim.Proof.ProofValue
+	}
+}
+
+// opinionKey identifies what a signer said about a subject
+type opinionKey struct {
+	signer  string
+	subject string
+}
+
+// latestOpinions returns each signer's latest unrevoked claim about each
+// subject
+func (n *Network) latestOpinions() map[opinionKey]*axiom.Claim {
+	latest := make(map[opinionKey]*axiom.Claim)
+	for _, claim := range n.claims {
+		if claim.IsPrivate() || n.isRevoked(claim) {
+			continue
+		}
+		for _, signer := range claim.Signers() {
+			key := opinionKey{signer, claim.ClaimBody.Subject}
+			if previous, ok := latest[key]; !ok || laterClaim(claim, previous) {
+				latest[key] = claim
+			}
+		}
+	}
+	return latest
+}
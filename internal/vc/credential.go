@@ -0,0 +1,262 @@
+package vc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"axia/internal/axiom"
+	"axia/internal/canon"
+)
+
+const (
+	// ContextV2 is the base context of VC Data Model 2.0 credentials
+	ContextV2 = "https://www.w3.org/ns/credentials/v2"
+
+	// ContextAxiomatic defines the axiomatic claim terms
+	ContextAxiomatic = "https://schema.axios.ai/AxiomaticClaim.jsonld"
+
+	TypeCredential = "VerifiableCredential"
+	TypeAxiomatic  = "AxiomaticClaim"
+
+	claimBodyContext = "https://schema.axios.ai/"
+)
+
+var (
+	ErrNotAxiomatic     = errors.New("credential is not an axiomatic claim")
+	ErrNotRepresentable = errors.New("credential cannot be represented as a claim")
+)
+
+// Credential is a VC Data Model 2.0 credential carrying an axiomatic claim
+type Credential struct {
+	Context           []string `json:"@context"`
+	ID                string   `json:"id,omitempty"`
+	Type              []string `json:"type"`
+	Issuer            string   `json:"issuer"`
+	ValidFrom         string   `json:"validFrom"`
+	CredentialSubject Subject  `json:"credentialSubject"`
+	Proof             *Proof   `json:"proof,omitempty"`
+}
+
+// Subject is the credentialSubject of an axiomatic claim credential
+type Subject struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type,omitempty"`
+	Agent  string   `json:"agent"`
+	Tags   []string `json:"tags,omitempty"`
+	Rating Rating   `json:"axiomRating"`
+}
+
+// Rating mirrors axiom.AxiomRating without its embedded context
+type Rating struct {
+	Type            string  `json:"type"`
+	MaxConfidence   float64 `json:"maxConfidence"`
+	MinConfidence   float64 `json:"minConfidence"`
+	ConfidenceValue float64 `json:"confidenceValue"`
+	Axiom           string  `json:"axiom"`
+}
+
+// Parse decodes a credential and rejects documents with members the
+// Credential type cannot hold, since they would be silently dropped and
+// the credential could no longer be verified after conversion
+func Parse(data []byte) (*Credential, error) {
+	cred := &Credential{}
+	if err := json.Unmarshal(data, cred); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRepresentable, err)
+	}
+
+	original, err := canon.JCS(data)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := canon.Marshal(cred)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(original, decoded) {
+		return nil, fmt.Errorf("%w: unsupported or malformed members", ErrNotRepresentable)
+	}
+
+	return cred, nil
+}
+
+// ReadCredentials decodes credentials from r. The input may be a single
+// credential, a JSON array of credentials, or one credential per line.
+func ReadCredentials(r io.Reader) ([]*Credential, error) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+
+	var raw []json.RawMessage
+	first, err := br.Peek(1)
+	for err == nil && isSpace(first[0]) {
+		br.ReadByte()
+		first, err = br.Peek(1)
+	}
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	if first[0] == '[' {
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to decode credential array: %w", err)
+		}
+	} else {
+		for {
+			var msg json.RawMessage
+			err := dec.Decode(&msg)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode credential %d: %w", len(raw)+1, err)
+			}
+			raw = append(raw, msg)
+		}
+	}
+
+	creds := make([]*Credential, 0, len(raw))
+	for i, msg := range raw {
+		cred, err := Parse(msg)
+		if err != nil {
+			return nil, fmt.Errorf("credential %d: %w", i+1, err)
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// FromClaim converts a claim to an unsigned credential. A Data Integrity
+// proof carried by the claim, e.g. one kept from an imported credential,
+// is attached unchanged; other proof types are dropped.
+func FromClaim(claim *axiom.Claim) *Credential {
+	body := claim.ClaimBody
+	cred := &Credential{
+		Context:   []string{ContextV2, ContextAxiomatic},
+		ID:        claim.ID,
+		Type:      []string{TypeCredential, TypeAxiomatic},
+		Issuer:    claim.Issuer,
+		ValidFrom: claim.Issued.UTC().Format(time.RFC3339),
+		CredentialSubject: Subject{
+			ID:    body.Subject,
+			Type:  body.Type,
+			Agent: body.Agent,
+			Tags:  body.Tags,
+			Rating: Rating{
+				Type:            body.Rating.Type,
+				MaxConfidence:   body.Rating.MaxConfidence,
+				MinConfidence:   body.Rating.MinConfidence,
+				ConfidenceValue: body.Rating.ConfidenceValue,
+				Axiom:           body.Rating.Axiom,
+			},
+		},
+	}
+
+	if claim.Proof.Type == ProofTypeDataIntegrity {
+		cred.Proof = &Proof{
+			Type:               claim.Proof.Type,
+			Cryptosuite:        claim.Proof.Cryptosuite,
+			Created:            claim.Proof.Created.UTC().Format(time.RFC3339),
+			VerificationMethod: claim.Proof.Verifier.ID,
+			ProofPurpose:       claim.Proof.ProofPurpose,
+			Domain:             claim.Proof.Domain,
+			ProofValue:         claim.Proof.ProofValue,
+		}
+	}
+	return cred
+}
+
+// ToClaim converts a credential to a claim that keeps the credential's
+// Data Integrity proof. The proof is not checked here; claims are verified
+// with axiom.VerifyProof like any other claim.
+func ToClaim(cred *Credential) (*axiom.Claim, error) {
+	if !equalStrings(cred.Type, []string{TypeCredential, TypeAxiomatic}) {
+		return nil, fmt.Errorf("%w: type %v", ErrNotAxiomatic, cred.Type)
+	}
+	if !equalStrings(cred.Context, []string{ContextV2, ContextAxiomatic}) {
+		return nil, fmt.Errorf("%w: @context %v", ErrNotAxiomatic, cred.Context)
+	}
+
+	issued, err := time.Parse(time.RFC3339, cred.ValidFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid validFrom: %v", ErrNotRepresentable, err)
+	}
+
+	subject := cred.CredentialSubject
+	claim := &axiom.Claim{
+		ID:      cred.ID,
+		Context: ContextAxiomatic,
+		Type:    TypeAxiomatic,
+		Issuer:  cred.Issuer,
+		Issued:  issued,
+		ClaimBody: axiom.Body{
+			Context: claimBodyContext,
+			Type:    subject.Type,
+			Subject: subject.ID,
+			Agent:   subject.Agent,
+			Tags:    subject.Tags,
+			Rating: axiom.AxiomRating{
+				Context:         claimBodyContext,
+				Type:            subject.Rating.Type,
+				MaxConfidence:   subject.Rating.MaxConfidence,
+				MinConfidence:   subject.Rating.MinConfidence,
+				ConfidenceValue: subject.Rating.ConfidenceValue,
+				Axiom:           subject.Rating.Axiom,
+			},
+		},
+	}
+
+	if proof := cred.Proof; proof != nil {
+		created, err := time.Parse(time.RFC3339, proof.Created)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid proof created: %v", ErrNotRepresentable, err)
+		}
+		claim.Proof = axiom.Proof{
+			Type:         proof.Type,
+			Created:      created,
+			Verifier:     axiom.Verifier{ID: proof.VerificationMethod},
+			Domain:       proof.Domain,
+			ProofValue:   proof.ProofValue,
+			Cryptosuite:  proof.Cryptosuite,
+			ProofPurpose: proof.ProofPurpose,
+		}
+	}
+
+	// The claim must convert back to exactly the same credential, or the
+	// proof could not be verified from the claim later on
+	want, err := canon.Marshal(cred)
+	if err != nil {
+		return nil, err
+	}
+	got, err := canon.Marshal(FromClaim(claim))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(want, got) {
+		return nil, fmt.Errorf("%w: credential does not survive conversion", ErrNotRepresentable)
+	}
+
+	return claim, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package vc
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"axia/internal/axiom"
+	"axia/internal/canon"
+	"axia/internal/crypto"
+	"axia/internal/did"
+)
+
+const (
+	// ProofTypeDataIntegrity is the W3C Data Integrity proof type
+	ProofTypeDataIntegrity = "DataIntegrityProof"
+
+	// CryptosuiteEdDSAJCS signs JCS-canonicalized documents with Ed25519
+	CryptosuiteEdDSAJCS = "eddsa-jcs-2022"
+
+	// ProofPurposeAssertion is the purpose of proofs on issued credentials
+	ProofPurposeAssertion = "assertionMethod"
+)
+
+var (
+	ErrUnsigned               = errors.New("credential has no proof")
+	ErrUnsupportedCryptosuite = errors.New("unsupported cryptosuite")
+	ErrUnsupportedMethod      = errors.New("verification method must be a did:key")
+	ErrInvalidProof           = errors.New("invalid data integrity proof")
+	ErrSignerMismatch         = errors.New("signer is not the key that signed the claim")
+)
+
+func init() {
+	axiom.RegisterProofVerifier(ProofTypeDataIntegrity, func(claim *axiom.Claim) error {
+		return Verify(FromClaim(claim))
+	})
+}
+
+// Proof is a Data Integrity proof
+type Proof struct {
+	// Context is only set in the proof configuration that gets signed
+	Context            []string `json:"@context,omitempty"`
+	Type               string   `json:"type"`
+	Cryptosuite        string   `json:"cryptosuite"`
+	Created            string   `json:"created"`
+	VerificationMethod string   `json:"verificationMethod"`
+	ProofPurpose       string   `json:"proofPurpose"`
+	Domain             string   `json:"domain,omitempty"`
+	ProofValue         string   `json:"proofValue,omitempty"`
+}
+
+// VerificationMethod returns the did:key verification method URL of an
+// Ed25519 key
+func VerificationMethod(publicKey ed25519.PublicKey) string {
+	return did.VerificationMethodID(did.FromPublicKey(publicKey))
+}
+
+// Export converts a signed claim to a credential and signs it with an
+// eddsa-jcs-2022 proof. The signer must hold the key that signed the
+// claim, so an export never vouches for more than the claim itself did.
+func Export(claim *axiom.Claim, signer crypto.Signer) (*Credential, error) {
+	if err := axiom.VerifyProof(claim); err != nil {
+		return nil, err
+	}
+	// Credentials have no place for an encrypted body
+	if claim.IsPrivate() {
+		return nil, axiom.ErrEncryptedClaim
+	}
+	// or for the digests of concealed fields
+	if claim.IsSelective() {
+		return nil, axiom.ErrSelectiveClaim
+	}
+
+	claimKey, err := axiom.ParseKeyID(claim.Proof.Verifier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimKey.Equal(signer.PublicKey()) {
+		return nil, ErrSignerMismatch
+	}
+
+	cred := FromClaim(claim)
+	cred.Proof = nil
+	if err := Sign(cred, signer, time.Now()); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// Sign adds an eddsa-jcs-2022 proof to the credential, replacing any
+// existing proof
+func Sign(cred *Credential, signer crypto.Signer, created time.Time) error {
+	cred.Proof = nil
+
+	proof, err := SignDocument(cred, cred.Context, signer, created)
+	if err != nil {
+		return err
+	}
+	cred.Proof = proof
+	return nil
+}
+
+// Verify checks the credential's eddsa-jcs-2022 proof. Only did:key
+// verification methods are supported, so verification needs no network
+// access; binding a DID issuer to the key is left to axiom.VerifyIssuer.
+func Verify(cred *Credential) error {
+	if cred.Proof == nil {
+		return ErrUnsigned
+	}
+
+	unsecured := *cred
+	unsecured.Proof = nil
+	return VerifyDocument(&unsecured, cred.Context, cred.Proof)
+}
+
+// SignDocument creates an eddsa-jcs-2022 proof over any JSON-LD document
+// with the given @context. The document must not contain a proof.
+func SignDocument(doc interface{}, context []string, signer crypto.Signer, created time.Time) (*Proof, error) {
+	proof := &Proof{
+		Type:               ProofTypeDataIntegrity,
+		Cryptosuite:        CryptosuiteEdDSAJCS,
+		Created:            created.UTC().Format(time.RFC3339),
+		VerificationMethod: VerificationMethod(signer.PublicKey()),
+		ProofPurpose:       ProofPurposeAssertion,
+	}
+
+	input, err := hashData(doc, context, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign document: %w", err)
+	}
+
+	proof.ProofValue = crypto.EncodeMultibase(signature)
+	return proof, nil
+}
+
+// VerifyDocument checks an eddsa-jcs-2022 proof over doc, which must be
+// the secured document with its proof removed
+func VerifyDocument(doc interface{}, context []string, proof *Proof) error {
+	if proof == nil || proof.ProofValue == "" {
+		return ErrUnsigned
+	}
+	if proof.Type != ProofTypeDataIntegrity {
+		return fmt.Errorf("%w: proof type %s", ErrInvalidProof, proof.Type)
+	}
+	if proof.Cryptosuite != CryptosuiteEdDSAJCS {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCryptosuite, proof.Cryptosuite)
+	}
+	if proof.ProofPurpose != ProofPurposeAssertion {
+		return fmt.Errorf("%w: proof purpose %s", ErrInvalidProof, proof.ProofPurpose)
+	}
+	if proof.Context != nil && !equalStrings(proof.Context, context) {
+		return fmt.Errorf("%w: proof @context does not match document", ErrInvalidProof)
+	}
+
+	if !strings.HasPrefix(proof.VerificationMethod, "did:key:") {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMethod, proof.VerificationMethod)
+	}
+	publicKey, err := axiom.ParseKeyID(proof.VerificationMethod)
+	if err != nil {
+		return err
+	}
+
+	signature, err := crypto.DecodeMultibase(proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	input, err := hashData(doc, context, proof)
+	if err != nil {
+		return err
+	}
+	return crypto.Verify(publicKey, input, signature)
+}
+
+// hashData computes the eddsa-jcs-2022 signing input: the SHA-256 of the
+// canonical proof configuration followed by the SHA-256 of the canonical
+// unsecured document
+func hashData(unsecured interface{}, context []string, proof *Proof) ([]byte, error) {
+	config := *proof
+	config.ProofValue = ""
+	config.Context = context
+
+	canonicalConfig, err := canon.Marshal(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize proof configuration: %w", err)
+	}
+	canonicalDocument, err := canon.Marshal(unsecured)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize document: %w", err)
+	}
+
+	configHash := sha256.Sum256(canonicalConfig)
+	documentHash := sha256.Sum256(canonicalDocument)
+	return append(configHash[:], documentHash[:]...), nil
+}
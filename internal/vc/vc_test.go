@@ -0,0 +1,87 @@
+package vc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"axia/internal/axiom"
+	"axia/internal/crypto"
+	"axia/internal/did"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedClaim(t *testing.T) (*axiom.Claim, *crypto.KeyPair) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	key, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	issuer := did.FromPublicKey(key.PublicKey())
+	manager := axiom.NewManager(logger)
+	manager.RegisterSigner(issuer, key)
+
+	claim, err := manager.CreateClaim(issuer, "did:fact:sky", "Sky is blue", 0.99, []string{"physics"})
+	assert.NoError(t, err)
+	return claim, key
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	claim, key := newSignedClaim(t)
+
+	cred, err := Export(claim, key)
+	assert.NoError(t, err)
+	assert.Equal(t, claim.Issued.Format("2006-01-02T15:04:05Z"), cred.ValidFrom)
+	assert.Equal(t, CryptosuiteEdDSAJCS, cred.Proof.Cryptosuite)
+	assert.True(t, strings.HasPrefix(cred.Proof.ProofValue, "z"))
+	assert.NoError(t, Verify(cred))
+
+	data, err := json.Marshal(cred)
+	assert.NoError(t, err)
+	parsed, err := Parse(data)
+	assert.NoError(t, err)
+
+	imported, err := ToClaim(parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, claim.ClaimBody.Rating, imported.ClaimBody.Rating)
+	assert.NoError(t, axiom.VerifyClaim(context.Background(), did.NewRegistry(), imported))
+
+	// The imported claim's proof still covers every field
+	imported.ClaimBody.Rating.ConfidenceValue = 0.1
+	assert.ErrorIs(t, axiom.VerifyProof(imported), axiom.ErrProofVerification)
+}
+
+func TestExportRequiresClaimSigner(t *testing.T) {
+	claim, _ := newSignedClaim(t)
+
+	other, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	_, err = Export(claim, other)
+	assert.ErrorIs(t, err, ErrSignerMismatch)
+}
+
+func TestParseRejectsUnrepresentableCredentials(t *testing.T) {
+	claim, key := newSignedClaim(t)
+	cred, err := Export(claim, key)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(cred)
+	assert.NoError(t, err)
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	doc["validUntil"] = "2030-01-01T00:00:00Z"
+	extended, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	_, err = Parse(extended)
+	assert.ErrorIs(t, err, ErrNotRepresentable)
+
+	cred.ValidFrom = strings.Replace(cred.ValidFrom, "Z", "+00:00", 1)
+	_, err = ToClaim(cred)
+	assert.ErrorIs(t, err, ErrNotRepresentable)
+}